@@ -0,0 +1,111 @@
+package scale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/darrenmcc/run-scaler/internal/apiv2"
+)
+
+// APIVersion selects which Cloud Run Admin API is used to read and update services.
+type APIVersion int
+
+const (
+	// APIv1 is the Knative-compatible serving.knative.dev/v1 API.
+	APIv1 APIVersion = iota
+	// APIv2 is the Cloud Run v2 REST API. Only min and max instances are
+	// supported; options that change other revision settings are ignored.
+	// In dry run mode DryRunResult.Service returns nil and JSON returns the
+	// PATCH body.
+	APIv2
+)
+
+// WithAPIVersion selects the Cloud Run Admin API version. The default is APIv1.
+func WithAPIVersion(v APIVersion) ScaleOption {
+	return func(c *Config) {
+		c.APIVersion = v
+	}
+}
+
+// doerV2 adapts cfg.call to the apiv2 package.
+func (c *Client) doerV2(cfg *Config) apiv2.Doer {
+	return func(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
+		spanName := "run-scaler/get-service"
+		if method != http.MethodGet {
+			spanName = "run-scaler/update-service"
+		}
+		return cfg.call(ctx, c.httpClient, spanName, c.project, method, url, body)
+	}
+}
+
+// fromV2 converts errors from the apiv2 package to the ones this package documents.
+func fromV2(err error) error {
+	var se *apiv2.StatusError
+	if errors.As(err, &se) {
+		return &APIError{StatusCode: se.StatusCode, Body: se.Body}
+	}
+	return err
+}
+
+// scaleV2 is the APIv2 equivalent of scale.
+func (c *Client) scaleV2(ctx context.Context, cfg Config) (*ScaleResult, error) {
+	if err := cfg.checkService(); err != nil {
+		return nil, err
+	}
+	url := apiv2.ServiceURL(c.project, cfg.Region, cfg.Service)
+	svc, err := apiv2.GetService(ctx, c.doerV2(&cfg), url)
+	if err != nil {
+		return nil, fmt.Errorf("get service %s: %w", cfg.Service, fromV2(err))
+	}
+
+	oldMin, oldMax := apiv2.Scaling(svc)
+	if err := cfg.applyAdjust(oldMin, oldMax); err != nil {
+		return nil, err
+	}
+	max := cfg.revisionMax()
+	result := &ScaleResult{OldMin: oldMin, OldMax: oldMax, NewMin: cfg.Min, NewMax: max}
+	if oldMin == cfg.Min && oldMax == max {
+		cfg.log(ctx, "scale noop: service already at requested values",
+			"project", c.project, "service", cfg.Service, "region", cfg.Region)
+		result.Noop = true
+		c.scaled(ctx, &cfg, result)
+		return result, nil
+	}
+
+	// a max kept or cleared is one left unset, which v2 reads back as 0 like v1
+	patchMax := &max
+	if cfg.keepsMax() || cfg.clearsMax() {
+		patchMax = nil
+	}
+	if cfg.DryRun {
+		b, err := apiv2.ScalingPatch(cfg.Min, patchMax)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &DryRunResult{json: b}
+	}
+
+	_, err = apiv2.UpdateScaling(ctx, c.doerV2(&cfg), url, cfg.Min, patchMax)
+	if err != nil {
+		return nil, fmt.Errorf("update service %s: %w", cfg.Service, fromV2(err))
+	}
+	result.UpdatedAt = time.Now()
+	c.scaled(ctx, &cfg, result)
+	return result, nil
+}
+
+// getScalingV2 is the APIv2 equivalent of GetScaling.
+func (c *Client) getScalingV2(ctx context.Context, cfg Config) (int, int, error) {
+	if err := cfg.checkService(); err != nil {
+		return 0, 0, err
+	}
+	svc, err := apiv2.GetService(ctx, c.doerV2(&cfg), apiv2.ServiceURL(c.project, cfg.Region, cfg.Service))
+	if err != nil {
+		return 0, 0, fmt.Errorf("get service %s: %w", cfg.Service, fromV2(err))
+	}
+	min, max := apiv2.Scaling(svc)
+	return min, max, nil
+}
@@ -0,0 +1,93 @@
+package scale
+
+import (
+	"context"
+	"time"
+)
+
+// AuditEvent describes a successful scaling change, for compliance audit trails.
+type AuditEvent struct {
+	Timestamp      time.Time
+	ServiceName    string
+	Project        string
+	Region         string
+	OldMin, OldMax int
+	NewMin, NewMax int
+	// Initiator is who or what triggered the change, as set by WithInitiator.
+	Initiator    string
+	RevisionName string
+}
+
+// WithAuditLogger calls fn after every update Scale applies. Noops are not reported.
+func WithAuditLogger(fn func(ctx context.Context, event AuditEvent)) ScaleOption {
+	return func(c *Config) {
+		c.auditLogger = fn
+	}
+}
+
+// WithNoopCallback calls fn whenever Scale finds the service already at the requested
+// values and leaves it unchanged, with the service's min and max, e.g. to count
+// the runs that found nothing to do and tell them apart from runs that did not happen.
+func WithNoopCallback(fn func(ctx context.Context, service string, min, max int)) ScaleOption {
+	return func(c *Config) {
+		c.noopCallback = fn
+	}
+}
+
+// WithNotifier calls fn after every successful Scale, noops included, e.g. to post to
+// an ops channel; see the scalenotify package. An error from fn is logged (see
+// WithLogger) but does not fail Scale.
+func WithNotifier(fn func(ctx context.Context, event ScaleEvent) error) ScaleOption {
+	return func(c *Config) {
+		c.notifier = fn
+	}
+}
+
+// WithInitiator records who or what triggered the scaling, e.g. a user's email or
+// "cloud-scheduler/scale-up", as AuditEvent.Initiator.
+func WithInitiator(initiator string) ScaleOption {
+	return func(c *Config) {
+		c.Initiator = initiator
+	}
+}
+
+// scaled is called after every successful scale operation, including noops, to
+// report it to the hooks configured in cfg.
+func (c *Client) scaled(ctx context.Context, cfg *Config, result *ScaleResult) {
+	scaleOps.Add(1)
+	if result.Noop {
+		noops.Add(1)
+	} else {
+		lastScaled.Store(statusKey(c.project, cfg.Region, cfg.Service), result.UpdatedAt)
+	}
+	if cfg.noopCallback != nil && result.Noop {
+		cfg.noopCallback(ctx, cfg.Service, result.NewMin, result.NewMax)
+	}
+	if cfg.auditLogger != nil && !result.Noop {
+		cfg.auditLogger(ctx, AuditEvent{
+			Timestamp:    result.UpdatedAt,
+			ServiceName:  cfg.Service,
+			Project:      c.project,
+			Region:       cfg.Region,
+			OldMin:       result.OldMin,
+			OldMax:       result.OldMax,
+			NewMin:       result.NewMin,
+			NewMax:       result.NewMax,
+			Initiator:    cfg.Initiator,
+			RevisionName: result.RevisionName,
+		})
+	}
+	if cfg.historyStore != nil && !result.Noop {
+		if err := cfg.historyStore.Record(ctx, c.newScaleEvent(cfg, result)); err != nil {
+			cfg.log(ctx, "scale: recording history failed", "service", cfg.Service, "error", err)
+		}
+	}
+	if cfg.notifier != nil {
+		if err := cfg.notifier(ctx, c.newScaleEvent(cfg, result)); err != nil {
+			cfg.log(ctx, "scale: notifying failed", "service", cfg.Service, "error", err)
+		}
+	}
+	if cfg.eventBus != nil {
+		cfg.eventBus.publish(c.newScaleEvent(cfg, result))
+	}
+}
@@ -0,0 +1,112 @@
+package scale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	undeliveredMessagesMetric = "pubsub.googleapis.com/subscription/num_undelivered_messages"
+	// backlogLookback covers the few minutes Cloud Monitoring takes to make a
+	// Pub/Sub sample visible.
+	backlogLookback = 5 * time.Minute
+)
+
+// BacklogThreshold is a band of a subscription's backlog and the scaling to apply
+// while the backlog is in it. A band starts at MinMessages and runs up to the next
+// threshold's MinMessages.
+type BacklogThreshold struct {
+	MinMessages int64
+	Min, Max    int
+}
+
+// ScaleFromPubSubBacklog scales the service according to the number of undelivered
+// messages on the Pub/Sub subscription subscriptionID, in the service's project.
+// The latest value of the num_undelivered_messages metric selects the threshold
+// with the highest MinMessages not above it; it is an error if there is none, so
+// thresholds normally start with a MinMessages of 0. monClient is a Cloud
+// Monitoring metric client (monitoring.NewMetricClient).
+func ScaleFromPubSubBacklog(ctx context.Context, monClient *monitoring.MetricClient, subscriptionID string, thresholds []BacklogThreshold, opts ...ScaleOption) error {
+	if len(thresholds) == 0 {
+		return &ConfigError{Field: "thresholds", Message: "at least one threshold is required"}
+	}
+	c, err := NewClient(ctx, opts...)
+	if err != nil {
+		return err
+	}
+
+	backlog, err := subscriptionBacklog(ctx, monClient, c.project, subscriptionID)
+	if err != nil {
+		return err
+	}
+	t, ok := backlogThreshold(thresholds, backlog)
+	if !ok {
+		return fmt.Errorf("no threshold for backlog of %d messages on subscription %s", backlog, subscriptionID)
+	}
+	return c.Scale(ctx, t.Min, t.Max)
+}
+
+// subscriptionBacklog returns the most recent number of undelivered messages reported
+// for the subscription.
+func subscriptionBacklog(ctx context.Context, monClient *monitoring.MetricClient, project, subscriptionID string) (int64, error) {
+	filter := fmt.Sprintf(`metric.type = %q AND resource.labels.subscription_id = %q`, undeliveredMessagesMetric, subscriptionID)
+	p, err := latestPoint(ctx, monClient, project, filter, backlogLookback, nil)
+	if err != nil {
+		return 0, fmt.Errorf("subscription %s: %w", subscriptionID, err)
+	}
+	return p.GetValue().GetInt64Value(), nil
+}
+
+// latestPoint returns the most recent point within lookback of the first time series
+// matching filter, after aggregation if agg is not nil.
+func latestPoint(ctx context.Context, monClient *monitoring.MetricClient, project, filter string, lookback time.Duration, agg *monitoringpb.Aggregation) (*monitoringpb.Point, error) {
+	now := time.Now()
+	it := monClient.ListTimeSeries(ctx, &monitoringpb.ListTimeSeriesRequest{
+		Name:   "projects/" + project,
+		Filter: filter,
+		Interval: &monitoringpb.TimeInterval{
+			StartTime: timestamppb.New(now.Add(-lookback)),
+			EndTime:   timestamppb.New(now),
+		},
+		Aggregation: agg,
+		View:        monitoringpb.ListTimeSeriesRequest_FULL,
+	})
+	ts, err := it.Next()
+	if errors.Is(err, iterator.Done) {
+		return nil, errors.New("no metric data")
+	}
+	if err != nil {
+		return nil, err
+	}
+	// points are returned newest first
+	points := ts.GetPoints()
+	if len(points) == 0 {
+		return nil, errors.New("no metric data")
+	}
+	return points[0], nil
+}
+
+// backlogThreshold returns the threshold whose band contains backlog.
+func backlogThreshold(thresholds []BacklogThreshold, backlog int64) (BacklogThreshold, bool) {
+	sorted := append([]BacklogThreshold(nil), thresholds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].MinMessages < sorted[j].MinMessages })
+	var (
+		match BacklogThreshold
+		ok    bool
+	)
+	for _, t := range sorted {
+		if t.MinMessages > backlog {
+			break
+		}
+		match, ok = t, true
+	}
+	return match, ok
+}
@@ -0,0 +1,73 @@
+package scale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+// ErrNoRowsReturned is returned by ScaleFromBigQuery when the query has no results.
+// The service is left unchanged.
+var ErrNoRowsReturned = errors.New("scale: query returned no rows")
+
+// ScaleFromBigQuery runs query and scales the service to the integer values of the
+// minCol and maxCol columns of the first row, e.g. to size a service ahead of the
+// batch loads a pipeline has scheduled.
+func ScaleFromBigQuery(ctx context.Context, bqClient *bigquery.Client, query string, minCol, maxCol string, opts ...ScaleOption) error {
+	c, err := NewClient(ctx, opts...)
+	if err != nil {
+		return err
+	}
+
+	it, err := bqClient.Query(query).Read(ctx)
+	if err != nil {
+		return err
+	}
+	row := map[string]bigquery.Value{}
+	if err := it.Next(&row); err != nil {
+		if errors.Is(err, iterator.Done) {
+			return ErrNoRowsReturned
+		}
+		return err
+	}
+
+	min, err := columnInt(row, minCol)
+	if err != nil {
+		return err
+	}
+	max, err := columnInt(row, maxCol)
+	if err != nil {
+		return err
+	}
+	return c.Scale(ctx, min, max)
+}
+
+// columnInt returns the named column of row as an int. BigQuery returns INT64
+// columns as int64; numeric strings are accepted too so the query can cast freely.
+func columnInt(row map[string]bigquery.Value, col string) (int, error) {
+	v, ok := row[col]
+	if !ok {
+		return 0, fmt.Errorf("missing column %s", col)
+	}
+	switch v := v.(type) {
+	case int64:
+		return int(v), nil
+	case float64:
+		if v != float64(int64(v)) {
+			return 0, fmt.Errorf("invalid column %s value %v: must be an integer", col, v)
+		}
+		return int(v), nil
+	case string:
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid column %s value %q: must be an integer", col, v)
+		}
+		return n, nil
+	default:
+		return 0, fmt.Errorf("invalid column %s value %v: must be an integer", col, v)
+	}
+}
@@ -0,0 +1,131 @@
+package scale
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by Scale without calling the Cloud Run API while the
+// circuit breaker set with WithCircuitBreaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open: Cloud Run API calls suspended after repeated failures")
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// breaker is a circuit breaker guarding calls to the Cloud Run API.
+// A nil *breaker allows every call.
+type breaker struct {
+	failureThreshold int
+	resetTimeout     time.Duration
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+// WithCircuitBreaker stops Scale from calling the Cloud Run API for resetTimeout after
+// failureThreshold consecutive failures, returning ErrCircuitOpen instead. Once
+// resetTimeout has passed a single trial call is let through: if it succeeds the
+// breaker closes, otherwise it opens again.
+//
+// The breaker's state lives in the returned option, so create it once and pass the
+// same option to every call (or to NewClient) e.g.
+//
+//	var breaker = scale.WithCircuitBreaker(5, 10*time.Minute)
+//	...
+//	scale.Scale(ctx, scale.WithMin(1), scale.WithMax(10), breaker)
+func WithCircuitBreaker(failureThreshold int, resetTimeout time.Duration) ScaleOption {
+	b := &breaker{failureThreshold: failureThreshold, resetTimeout: resetTimeout}
+	return func(c *Config) {
+		c.breaker = b
+	}
+}
+
+// allow reports ErrCircuitOpen if a call should not be made now.
+func (b *breaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.resetTimeout {
+			return ErrCircuitOpen
+		}
+		b.state = breakerHalfOpen
+		return nil
+	case breakerHalfOpen:
+		// a trial call is already in flight
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+// record updates the breaker with the outcome of a call it allowed.
+func (b *breaker) record(err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.failureThreshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// abandon updates the breaker for a call it allowed that said nothing about the
+// API's health. If it was the trial call the breaker stays open, letting the next
+// call be the trial instead.
+func (b *breaker) abandon() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+	}
+}
+
+// inconclusive reports whether err from a scale operation says nothing either way
+// about the Cloud Run API: the caller gave up, e.g. a client disconnecting from a
+// handler, another scale held the lock, or the request was refused as invalid
+// without updating the service.
+func inconclusive(err error) bool {
+	var (
+		invalid *ValidationError
+		config  *ConfigError
+	)
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, ErrScaleInProgress) || errors.As(err, &invalid) || errors.As(err, &config)
+}
+
+// countsAsFailure reports whether err from a scale operation indicates a problem
+// with the Cloud Run API rather than with the request itself.
+func countsAsFailure(err error) bool {
+	var (
+		dry     *DryRunResult
+		refused *refusedError
+	)
+	return err != nil && !errors.As(err, &dry) && !errors.As(err, &refused) &&
+		!inconclusive(err) && !errors.Is(err, ErrConcurrentModification)
+}
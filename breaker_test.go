@@ -0,0 +1,52 @@
+package scale_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	scale "github.com/darrenmcc/run-scaler"
+	"github.com/darrenmcc/run-scaler/scaletest"
+)
+
+func TestCircuitBreakerIgnoresCancellation(t *testing.T) {
+	srv, _ := scaletest.NewTestServer(t, newService(nil))
+	opts := append(serverOptions(srv), scale.WithCircuitBreaker(1, time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := scale.ScaleMinMax(ctx, 1, 10, opts...); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Scale with a cancelled context = %v, want context.Canceled", err)
+	}
+	if err := scale.ScaleMinMax(context.Background(), 1, 10, opts...); err != nil {
+		t.Errorf("Scale after a cancelled call = %v, want the breaker still closed", err)
+	}
+}
+
+func TestCircuitBreakerTrialIgnoresValidation(t *testing.T) {
+	srv, _ := scaletest.NewTestServer(t, newService(nil))
+	opts := append(serverOptions(srv), scale.WithCircuitBreaker(2, 10*time.Millisecond))
+	missing := append(opts, scale.WithService("missing-svc"))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := scale.ScaleMinMax(ctx, 1, 10, missing...); err == nil {
+			t.Fatal("Scale of a missing service succeeded")
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// the trial call is refused by validation after reading the service, which says
+	// nothing about the API, so the next call is the trial instead
+	var invalid *scale.ValidationError
+	if _, err := scale.ScaleMaxBy(ctx, 5, opts...); !errors.As(err, &invalid) {
+		t.Fatalf("ScaleMaxBy on a service without max = %v, want a *ValidationError", err)
+	}
+	if err := scale.ScaleMinMax(ctx, 1, 10, missing...); err == nil || errors.Is(err, scale.ErrCircuitOpen) {
+		t.Fatalf("trial call = %v, want it let through and failing", err)
+	}
+	if err := scale.ScaleMinMax(ctx, 1, 10, opts...); !errors.Is(err, scale.ErrCircuitOpen) {
+		t.Errorf("Scale after a failed trial = %v, want ErrCircuitOpen", err)
+	}
+}
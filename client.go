@@ -0,0 +1,193 @@
+package scale
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"cloud.google.com/go/compute/metadata"
+	"google.golang.org/api/run/v1"
+)
+
+// Client scales a Cloud Run service, resolving credentials and the project ID once
+// in NewClient instead of on every call like the package-level functions do.
+type Client struct {
+	httpClient *http.Client
+	project    string
+	opts       []ScaleOption
+}
+
+// NewClient creates a Client. The given options apply to every call made with it,
+// except WithMin and WithMax which are overridden by the arguments to Scale.
+func NewClient(ctx context.Context, opts ...ScaleOption) (*Client, error) {
+	cfg := newConfig(opts)
+	return newClient(ctx, &cfg, opts)
+}
+
+func newClient(ctx context.Context, cfg *Config, opts []ScaleOption) (*Client, error) {
+	if cfg.err != nil {
+		return nil, cfg.err
+	}
+	httpClient, err := newHTTPClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	project := cfg.Project
+	if project == "" && cfg.KnativeEndpoint != "" {
+		project = defaultKnativeNamespace
+	}
+	if project == "" {
+		project, err = metadata.ProjectID()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Client{httpClient: httpClient, project: project, opts: opts}, nil
+}
+
+// Scale sets the min and max instances of the service. See the package-level Scale.
+func (c *Client) Scale(ctx context.Context, min, max int) error {
+	c, cfg := c.config(ctx, WithMin(min), WithMax(max))
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	_, err := c.scale(ctx, cfg)
+	return err
+}
+
+// GetScaling returns the current min and max instances of the service.
+// See the package-level GetScaling.
+func (c *Client) GetScaling(ctx context.Context) (int, int, error) {
+	c, cfg := c.config(ctx)
+	if cfg.APIVersion == APIv2 {
+		return c.getScalingV2(ctx, cfg)
+	}
+	svc, err := c.get(ctx, &cfg)
+	if err != nil {
+		return 0, 0, err
+	}
+	return currentScaling(svc)
+}
+
+// NewHandler is like the package-level NewHandler but scales using c.
+func (c *Client) NewHandler(min, max int) func(http.ResponseWriter, *http.Request) {
+	cfg := newConfig(withOptions(c.opts, WithMin(min), WithMax(max)))
+	return cfg.wrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, cfg := c.config(r.Context(), WithMin(min), WithMax(max))
+		var result *ScaleResult
+		err := cfg.validate()
+		if err == nil {
+			result, err = c.scale(r.Context(), cfg)
+		}
+		writeScaleResult(w, result, err, cfg.JSONResponse)
+	})).ServeHTTP
+}
+
+// config builds the Config for a single call made with ctx from the client's options,
+// then the project and service set in ctx, then extra. It also returns the client to
+// make the call with, a copy of c if ctx selects another project.
+func (c *Client) config(ctx context.Context, extra ...ScaleOption) (*Client, Config) {
+	cfg := newConfig(withOptions(c.opts, append(contextOptions(ctx), extra...)...))
+	if cfg.Project != "" && cfg.Project != c.project {
+		cc := *c
+		cc.project = cfg.Project
+		c = &cc
+	}
+	return c, cfg
+}
+
+// servingURL returns the base URL of the serving.knative.dev/v1 API for the
+// client's project (or Knative namespace).
+func (c *Client) servingURL(cfg *Config) string {
+	base := fmt.Sprintf("https://%s-run.googleapis.com", cfg.Region)
+	if cfg.KnativeEndpoint != "" {
+		base = strings.TrimSuffix(cfg.KnativeEndpoint, "/")
+	}
+	return fmt.Sprintf("%s/apis/serving.knative.dev/v1/namespaces/%s", base, c.project)
+}
+
+func (c *Client) serviceURL(cfg *Config) string {
+	return c.servingURL(cfg) + "/services/" + cfg.Service
+}
+
+// get fetches the current state of the service from the Cloud Run API.
+func (c *Client) get(ctx context.Context, cfg *Config) (*run.Service, error) {
+	if err := cfg.checkService(); err != nil {
+		return nil, err
+	}
+	svcResp, err := cfg.call(ctx, c.httpClient, "run-scaler/get-service", c.project, http.MethodGet, c.serviceURL(cfg), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer svcResp.Body.Close()
+
+	if svcResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get service %s: %w", cfg.Service, newAPIError(svcResp))
+	}
+
+	var svc run.Service
+	err = json.NewDecoder(svcResp.Body).Decode(&svc)
+	if err != nil {
+		return nil, err
+	}
+	initTemplate(&svc)
+	return &svc, nil
+}
+
+// initTemplate allocates whatever parts of svc's metadata and revision template are
+// missing, e.g. the annotations of a service created without any, so that they can
+// be written to and read without checking for nil.
+func initTemplate(svc *run.Service) {
+	if svc.Metadata == nil {
+		svc.Metadata = &run.ObjectMeta{}
+	}
+	if svc.Metadata.Annotations == nil {
+		svc.Metadata.Annotations = make(map[string]string)
+	}
+	if svc.Spec == nil {
+		svc.Spec = &run.ServiceSpec{}
+	}
+	if svc.Spec.Template == nil {
+		svc.Spec.Template = &run.RevisionTemplate{}
+	}
+	tmpl := svc.Spec.Template
+	if tmpl.Metadata == nil {
+		tmpl.Metadata = &run.ObjectMeta{}
+	}
+	if tmpl.Metadata.Annotations == nil {
+		tmpl.Metadata.Annotations = make(map[string]string)
+	}
+	if tmpl.Spec == nil {
+		tmpl.Spec = &run.RevisionSpec{}
+	}
+}
+
+// update submits the modified service to the Cloud Run API and returns the
+// service as reported in the response.
+func (c *Client) update(ctx context.Context, cfg *Config, b []byte) (*run.Service, error) {
+	updateResp, err := cfg.call(ctx, c.httpClient, "run-scaler/update-service", c.project, http.MethodPut, c.serviceURL(cfg), b)
+	if err != nil {
+		return nil, err
+	}
+	defer updateResp.Body.Close()
+
+	// the submitted service carries the resourceVersion it was read at, so Cloud Run
+	// refuses the update if the service changed in the meantime
+	if updateResp.StatusCode == http.StatusConflict {
+		return nil, fmt.Errorf("update service %s: %w: %w", cfg.Service, ErrConcurrentModification, newAPIError(updateResp))
+	}
+	if updateResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("update service %s: %w", cfg.Service, newAPIError(updateResp))
+	}
+
+	var svc run.Service
+	err = json.NewDecoder(updateResp.Body).Decode(&svc)
+	if err != nil {
+		return nil, err
+	}
+	return &svc, nil
+}
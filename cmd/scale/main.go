@@ -0,0 +1,135 @@
+// Command scale sets the min and max instances of a Cloud Run service, for one-off
+// scaling from CI pipelines such as GitHub Actions or Cloud Build e.g.
+//
+//	scale --project my-project --service api --region europe-west1 --min 5 --max 100
+//
+// Values can also be read from a YAML file with --config; flags take precedence:
+//
+//	project: my-project
+//	service: api
+//	region: europe-west1
+//	min: 5
+//	max: 100
+//
+// A JSON summary is printed to stdout. The exit code is 0 on success, including when
+// the service was already at the requested values, and 1 on error.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	scale "github.com/darrenmcc/run-scaler"
+	"gopkg.in/yaml.v3"
+)
+
+type config struct {
+	Project string `yaml:"project"`
+	Service string `yaml:"service"`
+	Region  string `yaml:"region"`
+	Min     *int   `yaml:"min"`
+	Max     *int   `yaml:"max"`
+}
+
+type summary struct {
+	OK      bool   `json:"ok"`
+	Noop    bool   `json:"noop"`
+	Project string `json:"project,omitempty"`
+	Service string `json:"service,omitempty"`
+	Region  string `json:"region,omitempty"`
+	OldMin  int    `json:"old_min"`
+	OldMax  int    `json:"old_max"`
+	Min     int    `json:"min"`
+	Max     int    `json:"max"`
+	Error   string `json:"error,omitempty"`
+}
+
+func main() {
+	var (
+		configPath = flag.String("config", "", "YAML file to read the values below from")
+		project    = flag.String("project", "", "project ID (default: from the metadata server)")
+		service    = flag.String("service", "", "service name (default: $K_SERVICE)")
+		region     = flag.String("region", "", "region (default: $K_REGION or us-central1)")
+		min        = flag.Int("min", 0, "min instances")
+		max        = flag.Int("max", 0, "max instances")
+	)
+	flag.Parse()
+
+	var cfg config
+	if *configPath != "" {
+		b, err := os.ReadFile(*configPath)
+		if err != nil {
+			fail(cfg, err)
+		}
+		if err := yaml.Unmarshal(b, &cfg); err != nil {
+			fail(cfg, fmt.Errorf("parsing %s: %w", *configPath, err))
+		}
+	}
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "project":
+			cfg.Project = *project
+		case "service":
+			cfg.Service = *service
+		case "region":
+			cfg.Region = *region
+		case "min":
+			cfg.Min = min
+		case "max":
+			cfg.Max = max
+		}
+	})
+	if cfg.Min == nil || cfg.Max == nil {
+		fail(cfg, errors.New("both --min and --max are required"))
+	}
+
+	var opts []scale.ScaleOption
+	if cfg.Project != "" {
+		opts = append(opts, scale.WithProject(cfg.Project))
+	}
+	if cfg.Service != "" {
+		opts = append(opts, scale.WithService(cfg.Service))
+	}
+	if cfg.Region != "" {
+		opts = append(opts, scale.WithRegion(cfg.Region))
+	}
+
+	// the result says what Scale actually found and did, noop included
+	result, err := scale.Scale(context.Background(), append(opts, scale.WithMin(*cfg.Min), scale.WithMax(*cfg.Max))...)
+	if err != nil {
+		fail(cfg, err)
+	}
+
+	s := newSummary(cfg)
+	s.OK = true
+	s.Noop = result.Noop
+	s.OldMin, s.OldMax = result.OldMin, result.OldMax
+	s.Min, s.Max = result.NewMin, result.NewMax
+	printSummary(s)
+}
+
+func newSummary(cfg config) summary {
+	s := summary{Project: cfg.Project, Service: cfg.Service, Region: cfg.Region}
+	if cfg.Min != nil {
+		s.Min = *cfg.Min
+	}
+	if cfg.Max != nil {
+		s.Max = *cfg.Max
+	}
+	return s
+}
+
+func fail(cfg config, err error) {
+	s := newSummary(cfg)
+	s.Error = err.Error()
+	printSummary(s)
+	os.Exit(1)
+}
+
+func printSummary(s summary) {
+	json.NewEncoder(os.Stdout).Encode(s)
+}
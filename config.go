@@ -0,0 +1,71 @@
+package scale
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ScalingConfig is the scaling state of a service, as stored in a config file e.g.
+//
+//	{"service": "api", "region": "europe-west1", "min": 5, "max": 100}
+//
+// Empty Service, Region and Project fall back to the same defaults as Scale.
+type ScalingConfig struct {
+	Service string `json:"service,omitempty"`
+	Region  string `json:"region,omitempty"`
+	Project string `json:"project,omitempty"`
+	Min     int    `json:"min"`
+	// Max is 0, or left out, for a service without max instances, which is
+	// restored by removing its max rather than writing 0.
+	Max int `json:"max,omitempty"`
+	// ConcurrencyTarget is the target number of concurrent requests per instance;
+	// 0 leaves it unchanged.
+	ConcurrencyTarget int `json:"concurrency_target,omitempty"`
+}
+
+// LoadConfig reads a ScalingConfig from JSON. Unknown fields are rejected so that a
+// misspelt key is not silently ignored.
+func LoadConfig(r io.Reader) (ScalingConfig, error) {
+	var cfg ScalingConfig
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return ScalingConfig{}, fmt.Errorf("invalid scaling config: %w", err)
+	}
+	return cfg, nil
+}
+
+// ApplyConfig scales the service described by cfg to its values e.g. from a Cloud
+// Build step:
+//
+//	f, _ := os.Open("configs/scaling.json")
+//	cfg, err := scale.LoadConfig(f)
+//	...
+//	err = scale.ApplyConfig(ctx, cfg)
+func ApplyConfig(ctx context.Context, cfg ScalingConfig) error {
+	_, err := Scale(ctx, cfg.options()...)
+	return err
+}
+
+// options returns the ScaleOptions selecting and scaling the service described by cfg.
+func (cfg ScalingConfig) options() []ScaleOption {
+	opts := []ScaleOption{WithMin(cfg.Min), WithMax(cfg.Max)}
+	if cfg.Max == 0 {
+		opts = append(opts, withoutMax())
+	}
+	if cfg.Service != "" {
+		opts = append(opts, WithService(cfg.Service))
+	}
+	if cfg.Region != "" {
+		opts = append(opts, WithRegion(cfg.Region))
+	}
+	if cfg.Project != "" {
+		opts = append(opts, WithProject(cfg.Project))
+	}
+	if cfg.ConcurrencyTarget > 0 {
+		opts = append(opts, WithContainerConcurrency(cfg.ConcurrencyTarget))
+	}
+	return opts
+}
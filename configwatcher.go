@@ -0,0 +1,117 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v3"
+)
+
+// configDebounce is how long ConfigWatcher waits for a burst of file events, such as
+// an editor's save, to settle before applying the file.
+const configDebounce = 100 * time.Millisecond
+
+// ConfigWatcher keeps services scaled as listed in a YAML file, applying the file
+// again whenever it changes. The file has the form:
+//
+//	services:
+//	  - service: api
+//	    min: 5
+//	    max: 100
+//	  - service: worker
+//	    min: 0
+//	    max: 20
+type ConfigWatcher struct {
+	path string
+	opts []ScaleOption
+}
+
+// configFile is the format of the file read by ConfigWatcher.
+type configFile struct {
+	Services []struct {
+		Service string `yaml:"service"`
+		Min     int    `yaml:"min"`
+		Max     int    `yaml:"max"`
+	} `yaml:"services"`
+}
+
+// NewConfigWatcher returns a ConfigWatcher for the YAML file at path. opts apply to
+// every service, as in ScaleAll.
+func NewConfigWatcher(path string, opts ...ScaleOption) *ConfigWatcher {
+	return &ConfigWatcher{path: path, opts: opts}
+}
+
+// Run applies the file, then again after every change to it, until ctx is done; it
+// returns ctx.Err(), or an error if the file cannot be watched. Failures to read the
+// file or scale a service are logged (see WithLogger) and watching continues.
+func (w *ConfigWatcher) Run(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	// watch the directory, since editors often save by replacing the file
+	if err := watcher.Add(filepath.Dir(w.path)); err != nil {
+		return err
+	}
+
+	cfg := newConfig(w.opts)
+	w.apply(ctx, &cfg)
+
+	debounce := time.NewTimer(configDebounce)
+	debounce.Stop()
+	defer debounce.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return ctx.Err()
+			}
+			if filepath.Clean(event.Name) != filepath.Clean(w.path) || event.Has(fsnotify.Chmod) {
+				continue
+			}
+			debounce.Reset(configDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return ctx.Err()
+			}
+			cfg.log(ctx, "config watcher: watching failed", "path", w.path, "error", err)
+		case <-debounce.C:
+			w.apply(ctx, &cfg)
+		}
+	}
+}
+
+// apply scales the services listed in the file.
+func (w *ConfigWatcher) apply(ctx context.Context, cfg *Config) {
+	targets, err := w.load()
+	if err != nil {
+		cfg.log(ctx, "config watcher: reading config failed", "path", w.path, "error", err)
+		return
+	}
+	for _, err := range ScaleAll(ctx, targets, w.opts...) {
+		cfg.log(ctx, "config watcher: scaling failed", "path", w.path, "service", err.Service, "error", err.Err)
+	}
+}
+
+func (w *ConfigWatcher) load() ([]ServiceTarget, error) {
+	b, err := os.ReadFile(w.path)
+	if err != nil {
+		return nil, err
+	}
+	var file configFile
+	if err := yaml.Unmarshal(b, &file); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", w.path, err)
+	}
+	targets := make([]ServiceTarget, len(file.Services))
+	for i, s := range file.Services {
+		targets[i] = ServiceTarget{Service: s.Service, Min: s.Min, Max: s.Max}
+	}
+	return targets, nil
+}
@@ -0,0 +1,35 @@
+package scale
+
+import "context"
+
+type (
+	projectKey struct{}
+	serviceKey struct{}
+)
+
+// ProjectFromContext returns a copy of ctx that makes Scale, and every other function,
+// Client method and handler given it, act on project, e.g. as taken from a JWT claim by
+// middleware. It takes precedence over WithProject, but not over WithProjectList.
+func ProjectFromContext(ctx context.Context, project string) context.Context {
+	return context.WithValue(ctx, projectKey{}, project)
+}
+
+// ServiceFromContext returns a copy of ctx that makes Scale, and every other function,
+// Client method and handler given it, act on service. It takes precedence over
+// WithService and K_SERVICE, but not over the services named in a call such as
+// ListScaling.
+func ServiceFromContext(ctx context.Context, service string) context.Context {
+	return context.WithValue(ctx, serviceKey{}, service)
+}
+
+// contextOptions returns the options selecting the project and service set in ctx.
+func contextOptions(ctx context.Context) []ScaleOption {
+	var opts []ScaleOption
+	if project, ok := ctx.Value(projectKey{}).(string); ok && project != "" {
+		opts = append(opts, WithProject(project))
+	}
+	if service, ok := ctx.Value(serviceKey{}).(string); ok && service != "" {
+		opts = append(opts, WithService(service))
+	}
+	return opts
+}
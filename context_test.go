@@ -0,0 +1,33 @@
+package scale_test
+
+import (
+	"context"
+	"testing"
+
+	scale "github.com/darrenmcc/run-scaler"
+	"github.com/darrenmcc/run-scaler/scaletest"
+)
+
+func TestServiceFromContextReads(t *testing.T) {
+	srv, c := scaletest.NewTestServer(t, newService(map[string]string{
+		"autoscaling.knative.dev/minScale": "2",
+		"autoscaling.knative.dev/maxScale": "10",
+	}), scale.WithService("other-svc"))
+	ctx := scale.ServiceFromContext(context.Background(), "my-svc")
+
+	min, max, err := c.GetScaling(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if min != 2 || max != 10 {
+		t.Errorf("GetScaling = %d, %d, want 2, 10", min, max)
+	}
+
+	info, err := scale.GetScalingInfo(ctx, append(serverOptions(srv), scale.WithService("other-svc"))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.MinInstances != 2 || info.MaxInstances != 10 {
+		t.Errorf("GetScalingInfo = %d, %d, want 2, 10", info.MinInstances, info.MaxInstances)
+	}
+}
@@ -0,0 +1,91 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	cpuUtilizationsMetric = "run.googleapis.com/container/cpu/utilizations"
+	instanceCountMetric   = "run.googleapis.com/container/instance_count"
+)
+
+// AutoScaleFromCPU sets the max instances of the service to the number of instances
+// needed to bring its CPU utilization to targetCPUPercent (e.g. 60), given its
+// average utilization and instance count over the last 5 minutes, clamped to
+// minInstances and maxInstances. min instances is set to minInstances. monClient is
+// a Cloud Monitoring metric client (monitoring.NewMetricClient).
+// This is a coarse complement to Cloud Run's own autoscaler, e.g. run from Cloud
+// Scheduler to keep max instances close to the load, not a replacement for it.
+func AutoScaleFromCPU(ctx context.Context, monClient *monitoring.MetricClient, targetCPUPercent float64, minInstances, maxInstances int, opts ...ScaleOption) error {
+	if targetCPUPercent <= 0 || targetCPUPercent > 100 {
+		return &ConfigError{Field: "targetCPUPercent", Message: fmt.Sprintf("%v must be above 0 and at most 100", targetCPUPercent)}
+	}
+	if err := validate(minInstances, maxInstances); err != nil {
+		return err
+	}
+	c, err := NewClient(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	c, cfg := c.config(ctx)
+
+	utilization, instances, err := c.serviceLoad(ctx, &cfg, monClient)
+	if err != nil {
+		return err
+	}
+	max := cpuTarget(utilization, instances, targetCPUPercent, minInstances, maxInstances)
+	cfg.log(ctx, "autoscale from cpu: scaling service", "service", cfg.Service,
+		"cpu_utilization", utilization, "instances", instances, "min", minInstances, "max", max)
+	return c.Scale(ctx, minInstances, max)
+}
+
+// serviceLoad returns the mean CPU utilization (0-1) of the service's containers and
+// its number of instances, over backlogLookback.
+func (c *Client) serviceLoad(ctx context.Context, cfg *Config, monClient *monitoring.MetricClient) (float64, int64, error) {
+	resource := fmt.Sprintf(`resource.type = "cloud_run_revision" AND resource.labels.service_name = %q AND resource.labels.location = %q`, cfg.Service, cfg.Region)
+	agg := func(aligner monitoringpb.Aggregation_Aligner, reducer monitoringpb.Aggregation_Reducer) *monitoringpb.Aggregation {
+		// one aligned point over the whole lookback, combined across revisions
+		return &monitoringpb.Aggregation{
+			AlignmentPeriod:    durationpb.New(backlogLookback),
+			PerSeriesAligner:   aligner,
+			CrossSeriesReducer: reducer,
+		}
+	}
+
+	p, err := latestPoint(ctx, monClient, c.project, fmt.Sprintf(`metric.type = %q AND %s`, cpuUtilizationsMetric, resource), backlogLookback,
+		agg(monitoringpb.Aggregation_ALIGN_MEAN, monitoringpb.Aggregation_REDUCE_MEAN))
+	if err != nil {
+		return 0, 0, fmt.Errorf("cpu utilization of service %s: %w", cfg.Service, err)
+	}
+	utilization := pointValue(p)
+
+	p, err = latestPoint(ctx, monClient, c.project, fmt.Sprintf(`metric.type = %q AND %s`, instanceCountMetric, resource), backlogLookback,
+		agg(monitoringpb.Aggregation_ALIGN_MAX, monitoringpb.Aggregation_REDUCE_SUM))
+	if err != nil {
+		return 0, 0, fmt.Errorf("instance count of service %s: %w", cfg.Service, err)
+	}
+	return utilization, int64(pointValue(p)), nil
+}
+
+// cpuTarget returns the number of instances that would run at targetPercent given
+// instances running at utilization, clamped to [min, max]. It is at least 1, so that an
+// idle service scaled to zero can still start an instance.
+func cpuTarget(utilization float64, instances int64, targetPercent float64, min, max int) int {
+	target := int(math.Ceil(float64(instances) * utilization * 100 / targetPercent))
+	if target < 1 {
+		target = 1
+	}
+	if target < min {
+		target = min
+	}
+	if target > max {
+		target = max
+	}
+	return target
+}
@@ -0,0 +1,117 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+	"google.golang.org/api/run/v1"
+)
+
+// newHTTPClient builds the client used to call the Cloud Run API from cfg's
+// credential options, defaulting to Application Default Credentials.
+func newHTTPClient(ctx context.Context, cfg *Config) (*http.Client, error) {
+	hc, err := credentialsClient(ctx, cfg)
+	if err != nil || (cfg.httpTimeout <= 0 && cfg.userAgent == "" && len(cfg.requestHeaders) == 0) {
+		return hc, err
+	}
+	// copy so that a client passed to WithHTTPClient is left untouched
+	wrapped := *hc
+	if cfg.httpTimeout > 0 {
+		wrapped.Timeout = cfg.httpTimeout
+	}
+	if cfg.userAgent != "" || len(cfg.requestHeaders) > 0 {
+		base := wrapped.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		wrapped.Transport = &headerTransport{userAgent: cfg.userAgent, header: cfg.requestHeaders, base: base}
+	}
+	return &wrapped, nil
+}
+
+// headerTransport prepends userAgent to the User-Agent of each request and adds
+// header to it.
+type headerTransport struct {
+	userAgent string
+	header    http.Header
+	base      http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// a RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	if t.userAgent != "" {
+		ua := t.userAgent
+		if existing := req.Header.Get("User-Agent"); existing != "" {
+			ua += " " + existing
+		}
+		req.Header.Set("User-Agent", ua)
+	}
+	for key, values := range t.header {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+func credentialsClient(ctx context.Context, cfg *Config) (*http.Client, error) {
+	if cfg.httpClient != nil {
+		return cfg.httpClient, nil
+	}
+	b, err := cfg.credentials()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ImpersonateServiceAccount != "" {
+		// the given credentials, if any, are the ones doing the impersonating
+		var opts []option.ClientOption
+		switch {
+		case cfg.tokenSource != nil:
+			opts = append(opts, option.WithTokenSource(cfg.tokenSource))
+		case b != nil:
+			opts = append(opts, option.WithCredentialsJSON(b))
+		}
+		ts, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+			TargetPrincipal: cfg.ImpersonateServiceAccount,
+			Scopes:          []string{run.CloudPlatformScope},
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return oauth2.NewClient(ctx, ts), nil
+	}
+	if cfg.tokenSource != nil {
+		return oauth2.NewClient(ctx, oauth2.ReuseTokenSource(nil, cfg.tokenSource)), nil
+	}
+	if b != nil {
+		creds, err := google.CredentialsFromJSON(ctx, b, run.CloudPlatformScope)
+		if err != nil {
+			return nil, fmt.Errorf("invalid credentials: %w", err)
+		}
+		return oauth2.NewClient(ctx, creds.TokenSource), nil
+	}
+	return google.DefaultClient(ctx, run.CloudPlatformScope)
+}
+
+// credentials returns the JSON credentials set by WithCredentialsJSON or
+// WithCredentialsFile, or nil to use Application Default Credentials.
+func (c *Config) credentials() ([]byte, error) {
+	if c.credentialsJSON != nil {
+		return c.credentialsJSON, nil
+	}
+	if c.credentialsFile == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(c.credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading credentials: %w", err)
+	}
+	return b, nil
+}
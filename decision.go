@@ -0,0 +1,50 @@
+package scale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrInvalidDecisionResponse is returned by ScaleFromURL when the decision endpoint's
+// response body is not a valid {"min":N,"max":M} document.
+var ErrInvalidDecisionResponse = errors.New("invalid decision response")
+
+// maxDecisionBody bounds how much of a decision response ScaleFromURL reads.
+const maxDecisionBody = 1 << 20
+
+// ScaleFromURL GETs decisionURL and scales the service to the values in the JSON
+// response, which must be {"min":N,"max":M}, so that capacity can be decided by a
+// separate service. client makes the request and may carry its credentials; nil
+// means http.DefaultClient. A non-200 response is an error, and a malformed body or
+// invalid values are reported as ErrInvalidDecisionResponse, all without scaling.
+func ScaleFromURL(ctx context.Context, decisionURL string, client *http.Client, opts ...ScaleOption) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, decisionURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("decision endpoint %s responded %s", decisionURL, resp.Status)
+	}
+	min, max, err := decodeScaling(io.LimitReader(resp.Body, maxDecisionBody))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidDecisionResponse, err)
+	}
+	if err := validate(min, max); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidDecisionResponse, err)
+	}
+	_, err = Scale(ctx, withOptions(opts, WithMin(min), WithMax(max))...)
+	return err
+}
@@ -0,0 +1,39 @@
+package scale
+
+import "context"
+
+// ScalingDiff describes the change Scale would make to a service's min and max
+// instances.
+type ScalingDiff struct {
+	OldMin, OldMax int
+	NewMin, NewMax int
+	// Changed is false if the service is already at the new values.
+	Changed bool
+}
+
+// Diff reports what scaling the service to min and max would change without changing
+// anything, e.g. to show the proposed change on a pull request before applying it.
+// The values are validated as Scale would.
+func Diff(ctx context.Context, min, max int, opts ...ScaleOption) (*ScalingDiff, error) {
+	c, err := NewClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c, cfg := c.config(ctx, WithMin(min), WithMax(max))
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	oldMin, oldMax, err := c.GetScaling(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// the max Scale writes, which WithPerRevisionMax may set apart from max
+	newMax := cfg.revisionMax()
+	return &ScalingDiff{
+		OldMin:  oldMin,
+		OldMax:  oldMax,
+		NewMin:  cfg.Min,
+		NewMax:  newMax,
+		Changed: oldMin != cfg.Min || oldMax != newMax,
+	}, nil
+}
@@ -0,0 +1,24 @@
+package scale_test
+
+import (
+	"context"
+	"testing"
+
+	scale "github.com/darrenmcc/run-scaler"
+	"github.com/darrenmcc/run-scaler/scaletest"
+)
+
+func TestDiffPerRevisionMax(t *testing.T) {
+	srv, _ := scaletest.NewTestServer(t, newService(map[string]string{
+		"autoscaling.knative.dev/minScale": "1",
+		"autoscaling.knative.dev/maxScale": "5",
+	}))
+
+	diff, err := scale.Diff(context.Background(), 1, 20, append(serverOptions(srv), scale.WithPerRevisionMax(5))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.NewMax != 5 || diff.Changed {
+		t.Errorf("NewMax = %d, Changed = %v, want 5, false", diff.NewMax, diff.Changed)
+	}
+}
@@ -0,0 +1,68 @@
+//go:build !nogokit
+
+package scale
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/darrenmcc/run-scaler/scalepb"
+	"github.com/go-kit/kit/endpoint"
+)
+
+// The go-kit endpoints are left out when building with -tags nogokit, for programs
+// that only use the HTTP handlers and do not want the go-kit dependency.
+
+// NewEndpoint can be used as a go-kit endpoint in any Gizmo service. The endpoint's
+// response is the *ScaleResult returned by Scale e.g.
+//
+//	"/scale/up": {
+//	    "POST": {
+//	        Endpoint: scale.NewEndpoint(scale.WithMin(100), scale.WithMax(1000)),
+//	    },
+//	},
+func NewEndpoint(opts ...ScaleOption) endpoint.Endpoint {
+	return func(ctx context.Context, _ interface{}) (interface{}, error) {
+		return Scale(ctx, opts...)
+	}
+}
+
+// NewEndpointFromRequest is like NewEndpoint but takes the min and max instances from
+// each request, using decode to read them from whatever request type the service
+// uses e.g.
+//
+//	scale.NewEndpointFromRequest(func(_ context.Context, req interface{}) (int, int, error) {
+//	    r := req.(*ScaleRequest)
+//	    return r.Min, r.Max, nil
+//	})
+//
+// An error from decode is returned without scaling.
+func NewEndpointFromRequest(decode func(ctx context.Context, request interface{}) (min, max int, err error), opts ...ScaleOption) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		min, max, err := decode(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		return Scale(ctx, withOptions(opts, WithMin(min), WithMax(max))...)
+	}
+}
+
+// NewEndpoint is like the package-level NewEndpoint but scales using c.
+func (c *Client) NewEndpoint(min, max int) endpoint.Endpoint {
+	return func(ctx context.Context, _ interface{}) (interface{}, error) {
+		return nil, c.Scale(ctx, min, max)
+	}
+}
+
+// NewGRPCEndpoint returns a go-kit endpoint taking a *scalepb.ScaleRequest and
+// responding with a *scalepb.ScaleResponse, for serving ScaleService through go-kit's
+// gRPC transport. Unlike NewGRPCServer, errors are returned as they are.
+func NewGRPCEndpoint(client *Client) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(*scalepb.ScaleRequest)
+		if !ok {
+			return nil, fmt.Errorf("unexpected request type %T", request)
+		}
+		return client.scaleRequest(ctx, req)
+	}
+}
@@ -0,0 +1,45 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+const (
+	minEnvVar = "SCALE_MIN"
+	maxEnvVar = "SCALE_MAX"
+)
+
+// ScaleFromEnvironment scales the service to the integers in the SCALE_MIN and
+// SCALE_MAX environment variables, so that scaling can be driven by e.g. a
+// ConfigMap or a job's environment without any code. If either is unset or not an
+// integer, a *ConfigError naming it is returned without scaling.
+func ScaleFromEnvironment(ctx context.Context, opts ...ScaleOption) error {
+	min, err := envInt(minEnvVar)
+	if err != nil {
+		return err
+	}
+	max, err := envInt(maxEnvVar)
+	if err != nil {
+		return err
+	}
+	if err := validate(min, max); err != nil {
+		return err
+	}
+	_, err = Scale(ctx, withOptions(opts, WithMin(min), WithMax(max))...)
+	return err
+}
+
+func envInt(key string) (int, error) {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return 0, &ConfigError{Field: key, Message: "not set"}
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, &ConfigError{Field: key, Message: fmt.Sprintf("%q is not an integer", v)}
+	}
+	return n, nil
+}
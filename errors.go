@@ -0,0 +1,167 @@
+package scale
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"google.golang.org/api/run/v1"
+)
+
+// ErrConcurrentModification is returned when the service was changed by someone else
+// between being read and being updated, on every attempt allowed by
+// WithConflictRetries.
+var ErrConcurrentModification = errors.New("service was modified concurrently")
+
+// APIError is returned when a Google Cloud API responds with an unexpected status
+// code, e.g. 403 when the caller lacks IAM permissions or 503 during an outage.
+type APIError struct {
+	StatusCode int
+	// Body is the start of the response body, which usually explains the error.
+	Body string
+}
+
+func (e *APIError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("API response code: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("API response code: %d: %s", e.StatusCode, e.Body)
+}
+
+// maxErrorBody is how much of an error response body is kept in an APIError.
+const maxErrorBody = 4 << 10
+
+func newAPIError(resp *http.Response) *APIError {
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return &APIError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(b))}
+}
+
+// ConfigError is returned when an option or environment variable is missing or invalid.
+type ConfigError struct {
+	// Field names the option or environment variable at fault.
+	Field   string
+	Message string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Message)
+}
+
+// checkInterval returns a *ConfigError for a polling interval that is not positive,
+// which time.NewTicker would panic on.
+func checkInterval(field string, d time.Duration) error {
+	if d <= 0 {
+		return &ConfigError{Field: field, Message: fmt.Sprintf("must be positive, got %v", d)}
+	}
+	return nil
+}
+
+// ValidationError is returned when the requested scaling values are rejected before
+// any call is made to the Cloud Run API.
+type ValidationError struct {
+	Min, Max int
+	Reason   string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid scaling values min=%d max=%d: %s", e.Min, e.Max, e.Reason)
+}
+
+// validate reports any error from applying the options, then checks the min and
+// max values.
+func (c *Config) validate() error {
+	if c.err != nil {
+		return c.err
+	}
+	if c.RevisionSuffix != "" {
+		if err := validateRevisionName(c.revisionName()); err != nil {
+			return err
+		}
+	}
+	return c.validateScaling()
+}
+
+// validateScaling checks Min and the revision max, including against the max cap.
+func (c *Config) validateScaling() error {
+	if c.keepsMax() || c.clearsMax() {
+		// only min is written, under whatever max the service has or its default
+		if c.Min < 0 {
+			return &ValidationError{Min: c.Min, Reason: "min must not be negative"}
+		}
+		return nil
+	}
+	max := c.revisionMax()
+	if err := validate(c.Min, max); err != nil {
+		return err
+	}
+	if maxCap := c.maxCap(); max > maxCap {
+		return &ValidationError{Min: c.Min, Max: max, Reason: fmt.Sprintf("max must not exceed the cap of %d", maxCap)}
+	}
+	return nil
+}
+
+// revisionNamePattern matches an RFC 1035 label, which Cloud Run requires of
+// revision names.
+var revisionNamePattern = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+
+func validateRevisionName(name string) error {
+	if len(name) > 63 || !revisionNamePattern.MatchString(name) {
+		return &ConfigError{
+			Field: "RevisionSuffix",
+			Message: fmt.Sprintf("revision name %q must be at most 63 lowercase letters, digits "+
+				"and hyphens, starting with a letter and not ending with a hyphen", name),
+		}
+	}
+	return nil
+}
+
+// checkService returns a *ConfigError if no service name was given.
+func (c *Config) checkService() error {
+	if c.Service == "" {
+		return &ConfigError{Field: "Service", Message: "not set; use WithService or set K_SERVICE"}
+	}
+	return nil
+}
+
+// validate checks the min and max values that will be sent to Cloud Run.
+func validate(min, max int) error {
+	var reason string
+	switch {
+	case min < 0:
+		reason = "min must not be negative"
+	case max < 0:
+		reason = "max must not be negative"
+	case max == 0 && min > 0:
+		reason = "max must be set when min is greater than zero"
+	case max < min:
+		reason = "max must not be less than min"
+	default:
+		return nil
+	}
+	return &ValidationError{Min: min, Max: max, Reason: reason}
+}
+
+// DryRunResult is returned by Scale when WithDryRun is set and the service would
+// have been updated. It carries the service exactly as it would have been submitted.
+type DryRunResult struct {
+	svc  *run.Service
+	json []byte
+}
+
+func (r *DryRunResult) Error() string {
+	return "dry run: update not applied"
+}
+
+// Service returns the service that would have been submitted to the Cloud Run API.
+func (r *DryRunResult) Service() *run.Service {
+	return r.svc
+}
+
+// JSON returns the request body that would have been sent to the Cloud Run API.
+func (r *DryRunResult) JSON() []byte {
+	return r.json
+}
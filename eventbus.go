@@ -0,0 +1,56 @@
+package scale
+
+import "sync/atomic"
+
+// EventBus delivers a ScaleEvent for every successful Scale made with WithEventBus,
+// noops included, on a buffered channel. Publishing never blocks Scale: when the
+// channel is full the event is dropped and counted in DroppedCount, so consumers
+// that fall behind lose events rather than slowing scaling down.
+type EventBus struct {
+	events  chan ScaleEvent
+	dropped atomic.Int64
+}
+
+// NewEventBus returns an EventBus buffering up to size events.
+func NewEventBus(size int) *EventBus {
+	return &EventBus{events: make(chan ScaleEvent, size)}
+}
+
+// Events returns the channel events are delivered on. It is never closed.
+func (b *EventBus) Events() <-chan ScaleEvent {
+	return b.events
+}
+
+// Drain removes and returns the events currently buffered, without waiting for more.
+func (b *EventBus) Drain() []ScaleEvent {
+	var events []ScaleEvent
+	for {
+		select {
+		case event := <-b.events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+// DroppedCount returns the number of events dropped because the channel was full.
+func (b *EventBus) DroppedCount() int64 {
+	return b.dropped.Load()
+}
+
+// publish delivers event if there is room for it and drops it otherwise.
+func (b *EventBus) publish(event ScaleEvent) {
+	select {
+	case b.events <- event:
+	default:
+		b.dropped.Add(1)
+	}
+}
+
+// WithEventBus publishes an event to bus after every successful Scale.
+func WithEventBus(bus *EventBus) ScaleOption {
+	return func(c *Config) {
+		c.eventBus = bus
+	}
+}
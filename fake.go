@@ -0,0 +1,56 @@
+package scale
+
+import (
+	"context"
+	"sync"
+)
+
+// Scaler is implemented by *Client and *FakeClient, so code that scales a service
+// can be tested without Google Cloud credentials.
+type Scaler interface {
+	Scale(ctx context.Context, min, max int) error
+	GetScaling(ctx context.Context) (int, int, error)
+}
+
+var (
+	_ Scaler = (*Client)(nil)
+	_ Scaler = (*FakeClient)(nil)
+)
+
+// ScaleCall records a single call to FakeClient.Scale.
+type ScaleCall struct {
+	Min, Max int
+}
+
+// FakeClient is an in-memory Scaler for tests. Scale validates its arguments like
+// Client does, then stores them and records the call in Calls.
+type FakeClient struct {
+	mu       sync.Mutex
+	min, max int
+	// Calls holds every call to Scale in order, including rejected ones.
+	Calls []ScaleCall
+}
+
+// NewFakeClient returns a FakeClient whose service starts at the given values.
+func NewFakeClient(initialMin, initialMax int) *FakeClient {
+	return &FakeClient{min: initialMin, max: initialMax}
+}
+
+// Scale records the call and, if min and max are valid, stores them.
+func (f *FakeClient) Scale(_ context.Context, min, max int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Calls = append(f.Calls, ScaleCall{Min: min, Max: max})
+	if err := validate(min, max); err != nil {
+		return err
+	}
+	f.min, f.max = min, max
+	return nil
+}
+
+// GetScaling returns the values most recently stored by Scale.
+func (f *FakeClient) GetScaling(context.Context) (int, int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.min, f.max, nil
+}
@@ -0,0 +1,39 @@
+package scale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+)
+
+// ScaleFromFirestore scales the service to the values stored in the Firestore
+// document at docPath (e.g. "scaling/my-service"), which must have integer min and
+// max fields. Pointing a scheduled job at it lets scaling policy be changed by
+// editing the document, taking effect on the next run without a redeploy.
+func ScaleFromFirestore(ctx context.Context, client *firestore.Client, docPath string, opts ...ScaleOption) error {
+	c, err := NewClient(ctx, opts...)
+	if err != nil {
+		return err
+	}
+
+	snap, err := client.Doc(docPath).Get(ctx)
+	if err != nil {
+		return err
+	}
+	var doc struct {
+		Min *int64 `firestore:"min"`
+		Max *int64 `firestore:"max"`
+	}
+	if err := snap.DataTo(&doc); err != nil {
+		return fmt.Errorf("document %s: %w", docPath, err)
+	}
+	if doc.Min == nil {
+		return fmt.Errorf("document %s: %w", docPath, errors.New("missing min"))
+	}
+	if doc.Max == nil {
+		return fmt.Errorf("document %s: %w", docPath, errors.New("missing max"))
+	}
+	return c.Scale(ctx, int(*doc.Min), int(*doc.Max))
+}
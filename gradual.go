@@ -0,0 +1,92 @@
+package scale
+
+import (
+	"context"
+	"time"
+)
+
+// gradualPollInterval is how often ScaleGradually checks whether a step's revision
+// is ready.
+const gradualPollInterval = 2 * time.Second
+
+// ScaleGradually moves the service's min and max instances towards targetMin and
+// targetMax by at most step at a time, waiting stepDelay between steps, so that a
+// large increase in min instances doesn't cold-start them all at once. After each
+// step that creates a revision, it also waits for the revision to be ready (see
+// WaitForReady) before the delay. If ctx is done mid-ramp, ScaleGradually stops and
+// returns ctx.Err(), leaving the service at the last step applied. A service without
+// max instances is given targetMax with the first step.
+func ScaleGradually(ctx context.Context, targetMin, targetMax, step int, stepDelay time.Duration, opts ...ScaleOption) error {
+	if step < 1 {
+		return &ConfigError{Field: "step", Message: "must be at least 1"}
+	}
+	if err := validate(targetMin, targetMax); err != nil {
+		return err
+	}
+	c, err := NewClient(ctx, opts...)
+	if err != nil {
+		return err
+	}
+
+	min, max, err := c.GetScaling(ctx)
+	if err != nil {
+		return err
+	}
+	if max == 0 {
+		// no max instances set, which leaves Cloud Run's default rather than a max
+		// of 0 to ramp up from; stepping from 0 would cap the service far below it
+		max = targetMax
+	}
+	for min != targetMin || max != targetMax {
+		min = stepTowards(min, targetMin, step)
+		max = stepTowards(max, targetMax, step)
+		// max may lag behind a rising min, or min behind a falling max
+		if max < min {
+			if targetMin > min {
+				max = min
+			} else {
+				min = max
+			}
+		}
+
+		c, cfg := c.config(ctx, WithMin(min), WithMax(max))
+		if err := cfg.validate(); err != nil {
+			return err
+		}
+		cfg.log(ctx, "scale gradually: applying step", "service", cfg.Service, "min", min, "max", max,
+			"target_min", targetMin, "target_max", targetMax)
+		result, err := c.scale(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		if result.RevisionName != "" {
+			if err := c.waitForReady(ctx, &cfg, result.RevisionName, gradualPollInterval); err != nil {
+				return err
+			}
+		}
+		if min == targetMin && max == targetMax {
+			return nil
+		}
+
+		t := time.NewTimer(stepDelay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	return nil
+}
+
+// stepTowards moves from current towards target by at most step.
+func stepTowards(current, target, step int) int {
+	switch {
+	case target > current+step:
+		return current + step
+	case target < current-step:
+		return current - step
+	default:
+		return target
+	}
+}
@@ -0,0 +1,39 @@
+package scale_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	scale "github.com/darrenmcc/run-scaler"
+	"github.com/darrenmcc/run-scaler/scaletest"
+)
+
+func TestScaleGraduallyWithoutMax(t *testing.T) {
+	srv, _ := scaletest.NewTestServer(t, newService(map[string]string{
+		"autoscaling.knative.dev/minScale": "0",
+	}))
+
+	if err := scale.ScaleGradually(context.Background(), 4, 100, 2, 0, serverOptions(srv)...); err != nil {
+		t.Fatal(err)
+	}
+
+	var maxes []int
+	for _, r := range scaletest.Requests(srv) {
+		if r.Method != http.MethodPut {
+			continue
+		}
+		svc := decodeService(t, r.Body)
+		max, _ := strconv.Atoi(svc.Spec.Template.Metadata.Annotations["autoscaling.knative.dev/maxScale"])
+		maxes = append(maxes, max)
+	}
+	if len(maxes) != 2 {
+		t.Fatalf("%d updates, want 2 steps of min", len(maxes))
+	}
+	for i, max := range maxes {
+		if max != 100 {
+			t.Errorf("step %d: maxScale = %d, want 100", i+1, max)
+		}
+	}
+}
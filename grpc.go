@@ -0,0 +1,81 @@
+package scale
+
+import (
+	"context"
+	"errors"
+
+	"github.com/darrenmcc/run-scaler/scalepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcServer serves ScaleService using a Client.
+type grpcServer struct {
+	scalepb.UnimplementedScaleServiceServer
+	client *Client
+}
+
+// NewGRPCServer returns a ScaleService implementation that scales using client, e.g.
+//
+//	s := grpc.NewServer()
+//	scalepb.RegisterScaleServiceServer(s, scale.NewGRPCServer(client))
+//
+// A request's service and region override the client's when set. Errors are
+// returned with a matching gRPC status code, e.g. InvalidArgument for a
+// ValidationError.
+func NewGRPCServer(client *Client) scalepb.ScaleServiceServer {
+	return &grpcServer{client: client}
+}
+
+func (s *grpcServer) Scale(ctx context.Context, req *scalepb.ScaleRequest) (*scalepb.ScaleResponse, error) {
+	resp, err := s.client.scaleRequest(ctx, req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return resp, nil
+}
+
+// scaleRequest applies a ScaleService request.
+func (c *Client) scaleRequest(ctx context.Context, req *scalepb.ScaleRequest) (*scalepb.ScaleResponse, error) {
+	opts := []ScaleOption{WithMin(int(req.GetMin())), WithMax(int(req.GetMax()))}
+	if req.GetService() != "" {
+		opts = append(opts, WithService(req.GetService()))
+	}
+	if req.GetRegion() != "" {
+		opts = append(opts, WithRegion(req.GetRegion()))
+	}
+	c, cfg := c.config(ctx, opts...)
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	result, err := c.scale(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &scalepb.ScaleResponse{Noop: result.Noop, RevisionName: result.RevisionName}, nil
+}
+
+// grpcError converts an error from Scale into a gRPC status error.
+func grpcError(err error) error {
+	var (
+		validationErr *ValidationError
+		configErr     *ConfigError
+		apiErr        *APIError
+	)
+	code := codes.Unknown
+	switch {
+	case errors.As(err, &validationErr), errors.As(err, &configErr):
+		code = codes.InvalidArgument
+	case errors.Is(err, ErrScaleInProgress), errors.Is(err, ErrConcurrentModification):
+		code = codes.Aborted
+	case errors.Is(err, ErrCircuitOpen):
+		code = codes.Unavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.As(err, &apiErr):
+		code = codes.Internal
+	}
+	return status.Error(code, err.Error())
+}
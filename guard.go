@@ -0,0 +1,69 @@
+package scale
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// NewScaleDownOnlyHandler is like NewHandler with WithMin(min) and WithMax(max), but
+// only ever lowers the service's scaling, e.g. for a quiet hours endpoint. If min or
+// max is above the service's current value nothing is changed and the handler
+// responds 409 with a JSON body explaining why. A service without max instances
+// counts as unbounded, so any max lowers it.
+func NewScaleDownOnlyHandler(min, max int, opts ...ScaleOption) http.HandlerFunc {
+	return newGuardedHandler(min, max, func(currentMin, currentMax int) error {
+		if min > currentMin || (currentMax != 0 && max > currentMax) {
+			return fmt.Errorf("refusing to scale up from min=%d max=%s to min=%d max=%d", currentMin, formatMax(currentMax), min, max)
+		}
+		return nil
+	}, opts)
+}
+
+// formatMax formats a service's current max instances, 0 if it has none.
+func formatMax(max int) string {
+	if max == 0 {
+		return "unset"
+	}
+	return strconv.Itoa(max)
+}
+
+// NewScaleUpOnlyHandler is the opposite of NewScaleDownOnlyHandler: it only ever
+// raises the service's scaling, e.g. for a traffic spike endpoint, and responds 409
+// if min or max is below the service's current value. A service without max
+// instances counts as unbounded, so any max would lower it and is refused.
+func NewScaleUpOnlyHandler(min, max int, opts ...ScaleOption) http.HandlerFunc {
+	return newGuardedHandler(min, max, func(currentMin, currentMax int) error {
+		if min < currentMin || currentMax == 0 || max < currentMax {
+			return fmt.Errorf("refusing to scale down from min=%d max=%s to min=%d max=%d", currentMin, formatMax(currentMax), min, max)
+		}
+		return nil
+	}, opts)
+}
+
+// refusedError is returned by Scale when the check set by a guarded handler refuses
+// the service's current values.
+type refusedError struct{ err error }
+
+func (e *refusedError) Error() string { return e.err.Error() }
+func (e *refusedError) Unwrap() error { return e.err }
+
+// newGuardedHandler returns a handler scaling the service to min and max, unless
+// check returns an error for its current values, which is written with 409. The
+// check is made on the values read for the update itself, so a service changed in
+// the meantime is checked again when the update is retried. Middleware set with
+// WithMiddleware wraps the handler.
+func newGuardedHandler(min, max int, check func(currentMin, currentMax int) error, opts []ScaleOption) http.HandlerFunc {
+	opts = withOptions(opts, WithMin(min), WithMax(max), func(c *Config) { c.check = check })
+	cfg := newConfig(opts)
+	return cfg.wrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, err := Scale(r.Context(), opts...)
+		var refused *refusedError
+		if errors.As(err, &refused) {
+			writeError(w, http.StatusConflict, refused.err)
+			return
+		}
+		writeScaleResult(w, result, err, cfg.JSONResponse)
+	})).ServeHTTP
+}
@@ -0,0 +1,92 @@
+package scale_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	scale "github.com/darrenmcc/run-scaler"
+	"github.com/darrenmcc/run-scaler/scaletest"
+)
+
+func TestScaleDownOnlyHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		noMax    bool
+		min, max int
+		want     int
+		updated  bool
+	}{
+		{"lower", false, 1, 5, http.StatusOK, true},
+		{"higher max", false, 1, 20, http.StatusConflict, false},
+		{"higher min", false, 3, 5, http.StatusConflict, false},
+		{"max below unset", true, 1, 1000, http.StatusOK, true},
+		{"higher min without max", true, 3, 1000, http.StatusConflict, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := map[string]string{
+				"autoscaling.knative.dev/minScale": "2",
+				"autoscaling.knative.dev/maxScale": "10",
+			}
+			if tt.noMax {
+				delete(annotations, "autoscaling.knative.dev/maxScale")
+			}
+			srv, _ := scaletest.NewTestServer(t, newService(annotations))
+			h := scale.NewScaleDownOnlyHandler(tt.min, tt.max, serverOptions(srv)...)
+
+			rec := httptest.NewRecorder()
+			h(rec, httptest.NewRequest(http.MethodPost, "/scale/down", nil))
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			var updated bool
+			for _, r := range scaletest.Requests(srv) {
+				updated = updated || r.Method == http.MethodPut
+			}
+			if updated != tt.updated {
+				t.Errorf("service updated = %v, want %v", updated, tt.updated)
+			}
+		})
+	}
+}
+
+func TestScaleUpOnlyHandler(t *testing.T) {
+	srv, _ := scaletest.NewTestServer(t, newService(map[string]string{
+		"autoscaling.knative.dev/minScale": "2",
+		"autoscaling.knative.dev/maxScale": "10",
+	}))
+	opts := append(serverOptions(srv), scale.WithJSONResponse())
+
+	rec := httptest.NewRecorder()
+	scale.NewScaleUpOnlyHandler(1, 20, opts...)(rec, httptest.NewRequest(http.MethodPost, "/scale/up", nil))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("scaling down: status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+
+	rec = httptest.NewRecorder()
+	scale.NewScaleUpOnlyHandler(5, 20, opts...)(rec, httptest.NewRequest(http.MethodPost, "/scale/up", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("scaling up: status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	annotations := scaletest.Service(srv).Spec.Template.Metadata.Annotations
+	if annotations["autoscaling.knative.dev/minScale"] != "5" || annotations["autoscaling.knative.dev/maxScale"] != "20" {
+		t.Errorf("annotations = %v, want minScale 5 and maxScale 20", annotations)
+	}
+}
+
+func TestScaleUpOnlyHandlerWithoutMax(t *testing.T) {
+	srv, _ := scaletest.NewTestServer(t, newService(map[string]string{
+		"autoscaling.knative.dev/minScale": "2",
+	}))
+
+	rec := httptest.NewRecorder()
+	scale.NewScaleUpOnlyHandler(5, 1000, serverOptions(srv)...)(rec, httptest.NewRequest(http.MethodPost, "/scale/up", nil))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if _, ok := scaletest.Service(srv).Spec.Template.Metadata.Annotations["autoscaling.knative.dev/maxScale"]; ok {
+		t.Error("unbounded max replaced by an explicit one")
+	}
+}
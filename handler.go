@@ -0,0 +1,206 @@
+package scale
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type scaleResponse struct {
+	OK       bool   `json:"ok"`
+	Noop     bool   `json:"noop"`
+	Revision string `json:"revision"`
+}
+
+type errorResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+// writeScaleResult reports the outcome of a scale operation, either as a bare status
+// code or, when asJSON is set, with a JSON body describing the result.
+func writeScaleResult(w http.ResponseWriter, result *ScaleResult, err error, asJSON bool) {
+	if err != nil {
+		status := errorStatus(err)
+		if asJSON {
+			writeError(w, status, err)
+			return
+		}
+		w.WriteHeader(status)
+		return
+	}
+	if !asJSON {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	resp := scaleResponse{OK: true}
+	if result != nil {
+		resp.Noop = result.Noop
+		resp.Revision = result.RevisionName
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// errorStatus returns the status code reporting a failed Scale: 400 for values
+// refused by validation, such as a max over the cap, which retrying cannot fix, and
+// 500 for anything else.
+func errorStatus(err error) int {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{OK: false, Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// NewDynamicHandler returns a handler that reads min and max from the request instead
+// of fixing them when the route is registered, either as query parameters
+// (/scale?min=5&max=100) or as a JSON body ({"min":5,"max":100}). Requests missing
+// either value or with invalid values are refused with 400. Responses always have a
+// JSON body like those written with WithJSONResponse.
+// Use WithAuthMiddleware to protect it, which runs inside any WithMiddleware, e.g.
+// router.Handle("/scale", scale.NewDynamicHandler(scale.WithAuthMiddleware(requireIAP)))
+func NewDynamicHandler(opts ...ScaleOption) http.HandlerFunc {
+	var h http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		min, max, err := scalingFromRequest(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := validate(min, max); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		result, err := Scale(r.Context(), withOptions(opts, WithMin(min), WithMax(max))...)
+		writeScaleResult(w, result, err, true)
+	})
+	cfg := newConfig(opts)
+	if cfg.authMiddleware != nil {
+		h = cfg.authMiddleware(h)
+	}
+	return cfg.wrapHandler(h).ServeHTTP
+}
+
+// NewHandlerWithAuth is like NewHandler with WithMin(min) and WithMax(max), but only
+// scales if the request has an Authorization: Bearer <token> header for which
+// validateToken returns true, e.g. comparing against a shared secret with
+// subtle.ConstantTimeCompare. Any other request is refused with 401. Middleware set
+// with WithMiddleware runs before the token is checked.
+func NewHandlerWithAuth(min, max int, validateToken func(string) bool, opts ...ScaleOption) http.HandlerFunc {
+	opts = withOptions(opts, WithMin(min), WithMax(max))
+	cfg := newConfig(opts)
+	return cfg.wrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if !ok || !validateToken(token) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			if cfg.JSONResponse {
+				writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+				return
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		result, err := Scale(r.Context(), opts...)
+		writeScaleResult(w, result, err, cfg.JSONResponse)
+	})).ServeHTTP
+}
+
+// bearerToken returns the token of the request's Authorization header, if it has a
+// non-empty one with the Bearer scheme.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// wrapHandler wraps h in the middleware set with WithMiddleware, the first one
+// outermost.
+func (c *Config) wrapHandler(h http.Handler) http.Handler {
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
+	}
+	return h
+}
+
+// scalingFromRequest reads min and max from the query string, or from a JSON body
+// if neither is in the query string.
+func scalingFromRequest(r *http.Request) (min, max int, err error) {
+	q := r.URL.Query()
+	if q.Has("min") || q.Has("max") {
+		if min, err = queryInt(q, "min"); err != nil {
+			return 0, 0, err
+		}
+		if max, err = queryInt(q, "max"); err != nil {
+			return 0, 0, err
+		}
+		return min, max, nil
+	}
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return 0, 0, errors.New("min and max must be given as query parameters or a JSON body")
+	}
+	return decodeScaling(r.Body)
+}
+
+// decodeScaling reads a JSON document of the form {"min":N,"max":M}.
+// Both fields are required.
+func decodeScaling(r io.Reader) (min, max int, err error) {
+	var body struct {
+		Min *int `json:"min"`
+		Max *int `json:"max"`
+	}
+	if err := json.NewDecoder(r).Decode(&body); err != nil {
+		return 0, 0, fmt.Errorf("invalid JSON body: %w", err)
+	}
+	if body.Min == nil {
+		return 0, 0, errors.New("missing min")
+	}
+	if body.Max == nil {
+		return 0, 0, errors.New("missing max")
+	}
+	return *body.Min, *body.Max, nil
+}
+
+func queryInt(q url.Values, key string) (int, error) {
+	if !q.Has(key) {
+		return 0, fmt.Errorf("missing %s", key)
+	}
+	n, err := strconv.Atoi(q.Get(key))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: must be an integer", key, q.Get(key))
+	}
+	return n, nil
+}
+
+// NewHealthHandler returns a handler that checks the Cloud Run Admin API can be reached
+// and the service read with the given options, for readiness or startup probes e.g.
+// router.HandleFunc("/healthz", scale.NewHealthHandler())
+// It responds 200 {"ok":true} if so and 503 {"ok":false,"error":"..."} if not.
+func NewHealthHandler(opts ...ScaleOption) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, _, err := GetScaling(r.Context(), opts...); err != nil {
+			writeError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, struct {
+			OK bool `json:"ok"`
+		}{OK: true})
+	}
+}
@@ -0,0 +1,89 @@
+package scale_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	scale "github.com/darrenmcc/run-scaler"
+	"github.com/darrenmcc/run-scaler/scaletest"
+	"google.golang.org/api/run/v1"
+)
+
+func newService(annotations map[string]string) *run.Service {
+	return &run.Service{
+		Metadata: &run.ObjectMeta{Name: "my-svc"},
+		Spec: &run.ServiceSpec{Template: &run.RevisionTemplate{
+			Metadata: &run.ObjectMeta{Annotations: annotations},
+		}},
+	}
+}
+
+// decodeService decodes the body of a PUT received by a test server.
+func decodeService(t *testing.T, body []byte) *run.Service {
+	t.Helper()
+	var svc run.Service
+	if err := json.Unmarshal(body, &svc); err != nil {
+		t.Fatalf("decoding update: %v", err)
+	}
+	return &svc
+}
+
+// serverOptions point the package-level functions at srv.
+func serverOptions(srv *httptest.Server) []scale.ScaleOption {
+	return []scale.ScaleOption{
+		scale.WithKnativeEndpoint(srv.URL),
+		scale.WithHTTPClient(srv.Client()),
+		scale.WithProject("default"),
+		scale.WithService("my-svc"),
+		scale.WithRegion("us-central1"),
+	}
+}
+
+func TestNewHandlerCancelledContext(t *testing.T) {
+	srv, _ := scaletest.NewTestServer(t, newService(nil))
+	h := scale.NewHandler(append(serverOptions(srv), scale.WithMin(1), scale.WithMax(10))...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodPost, "/scale", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		h(rec, req)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler did not return with a cancelled context")
+	}
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	for _, r := range scaletest.Requests(srv) {
+		if r.Method == http.MethodPut {
+			t.Errorf("service updated despite the cancelled context: %s", r.Body)
+		}
+	}
+}
+
+func TestNewDynamicHandlerOverCap(t *testing.T) {
+	srv, _ := scaletest.NewTestServer(t, newService(nil))
+	h := scale.NewDynamicHandler(append(serverOptions(srv), scale.WithMaxCap(10))...)
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodPost, "/scale?min=1&max=20", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if n := len(scaletest.Requests(srv)); n != 0 {
+		t.Errorf("%d calls made to the API, want none", n)
+	}
+}
@@ -0,0 +1,103 @@
+package scale
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// ScaleEvent describes a successful Scale of a service.
+type ScaleEvent struct {
+	Timestamp    time.Time `firestore:"timestamp" json:"timestamp"`
+	Project      string    `firestore:"project" json:"project"`
+	Region       string    `firestore:"region" json:"region"`
+	Service      string    `firestore:"service" json:"service"`
+	OldMin       int       `firestore:"old_min" json:"old_min"`
+	OldMax       int       `firestore:"old_max" json:"old_max"`
+	NewMin       int       `firestore:"new_min" json:"new_min"`
+	NewMax       int       `firestore:"new_max" json:"new_max"`
+	RevisionName string    `firestore:"revision_name" json:"revision_name"`
+	Initiator    string    `firestore:"initiator" json:"initiator,omitempty"`
+	// Noop is true if the service was already at the requested values. History
+	// stores only record updates, so it is only ever set for notifiers.
+	Noop bool `firestore:"noop" json:"noop"`
+}
+
+// HistoryStore keeps a record of the scaling changes applied to services.
+type HistoryStore interface {
+	Record(ctx context.Context, event ScaleEvent) error
+	// List returns up to limit of the service's most recent events, newest first.
+	List(ctx context.Context, service string, limit int) ([]ScaleEvent, error)
+}
+
+// WithHistoryStore records every update Scale applies in store. Noops are not
+// recorded. A failure to record is logged (see WithLogger) but does not fail Scale,
+// since the change has already been applied.
+func WithHistoryStore(store HistoryStore) ScaleOption {
+	return func(c *Config) {
+		c.historyStore = store
+	}
+}
+
+// newScaleEvent describes result as a ScaleEvent.
+func (c *Client) newScaleEvent(cfg *Config, result *ScaleResult) ScaleEvent {
+	timestamp := result.UpdatedAt
+	if timestamp.IsZero() {
+		// noops have no update time
+		timestamp = time.Now()
+	}
+	return ScaleEvent{
+		Timestamp:    timestamp,
+		Project:      c.project,
+		Region:       cfg.Region,
+		Service:      cfg.Service,
+		OldMin:       result.OldMin,
+		OldMax:       result.OldMax,
+		NewMin:       result.NewMin,
+		NewMax:       result.NewMax,
+		RevisionName: result.RevisionName,
+		Initiator:    cfg.Initiator,
+		Noop:         result.Noop,
+	}
+}
+
+// firestoreHistoryStore is a HistoryStore keeping one document per event.
+type firestoreHistoryStore struct {
+	client     *firestore.Client
+	collection string
+}
+
+// NewFirestoreHistoryStore returns a HistoryStore that adds a document per event to
+// the given Firestore collection. Listing needs a composite index on service
+// (ascending) and timestamp (descending), which Firestore offers to create on the
+// first query.
+func NewFirestoreHistoryStore(client *firestore.Client, collection string) HistoryStore {
+	return &firestoreHistoryStore{client: client, collection: collection}
+}
+
+func (s *firestoreHistoryStore) Record(ctx context.Context, event ScaleEvent) error {
+	_, _, err := s.client.Collection(s.collection).Add(ctx, event)
+	return err
+}
+
+func (s *firestoreHistoryStore) List(ctx context.Context, service string, limit int) ([]ScaleEvent, error) {
+	docs, err := s.client.Collection(s.collection).
+		Where("service", "==", service).
+		OrderBy("timestamp", firestore.Desc).
+		Limit(limit).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, err
+	}
+	events := make([]ScaleEvent, 0, len(docs))
+	for _, doc := range docs {
+		var e ScaleEvent
+		if err := doc.DataTo(&e); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
@@ -0,0 +1,95 @@
+package scale
+
+import (
+	"context"
+	"strconv"
+)
+
+// ScalingInfo is the current scaling and runtime configuration of a service, as
+// reported by GetScalingInfo.
+type ScalingInfo struct {
+	MinInstances, MaxInstances int
+	// ConcurrencyTarget is the autoscaling.knative.dev/target annotation, or the
+	// container concurrency if that is not set, 0 if neither is.
+	ConcurrencyTarget int
+	// CPUThrottling is true if CPU is only allocated during requests, Cloud Run's
+	// default.
+	CPUThrottling   bool
+	StartupCPUBoost bool
+	// ExecutionEnvironment is "gen1" or "gen2", or empty for Cloud Run's default.
+	ExecutionEnvironment string
+	LaunchStage          string
+	// LatestRevisionName is the latest revision that became ready.
+	LatestRevisionName string
+	// ReadyInstances is the number of instances Cloud Run reports for the latest
+	// ready revision. It only counts the instances kept by min instances, not the
+	// ones started by autoscaling.
+	ReadyInstances int
+}
+
+// GetScalingInfo returns the service's current scaling and runtime configuration,
+// for a fuller picture than GetScaling's min and max. With APIv2 only MinInstances
+// and MaxInstances are read.
+func GetScalingInfo(ctx context.Context, opts ...ScaleOption) (*ScalingInfo, error) {
+	c, err := NewClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetScalingInfo(ctx)
+}
+
+// GetScalingInfo returns the service's current scaling and runtime configuration.
+// See the package-level GetScalingInfo.
+func (c *Client) GetScalingInfo(ctx context.Context) (*ScalingInfo, error) {
+	c, cfg := c.config(ctx)
+	info := &ScalingInfo{}
+	if cfg.APIVersion == APIv2 {
+		var err error
+		if info.MinInstances, info.MaxInstances, err = c.getScalingV2(ctx, cfg); err != nil {
+			return nil, err
+		}
+		return info, nil
+	}
+
+	svc, err := c.get(ctx, &cfg)
+	if err != nil {
+		return nil, err
+	}
+	if info.MinInstances, info.MaxInstances, err = currentScaling(svc); err != nil {
+		return nil, err
+	}
+	tmpl := svc.Spec.Template
+	annotations := tmpl.Metadata.Annotations
+	if info.ConcurrencyTarget, err = annotationInt(annotations, targetAnnotation); err != nil {
+		return nil, err
+	}
+	if info.ConcurrencyTarget == 0 {
+		info.ConcurrencyTarget = int(tmpl.Spec.ContainerConcurrency)
+	}
+	info.CPUThrottling = annotationBool(annotations, cpuThrottlingAnnotation, true)
+	info.StartupCPUBoost = annotationBool(annotations, startupCPUBoostAnnotation, false)
+	info.ExecutionEnvironment = annotations[executionEnvironmentAnnotation]
+	info.LaunchStage = svc.Metadata.Annotations[launchStageAnnotation]
+
+	if svc.Status != nil && svc.Status.LatestReadyRevisionName != "" {
+		info.LatestRevisionName = svc.Status.LatestReadyRevisionName
+		rev, err := c.getRevision(ctx, &cfg, info.LatestRevisionName)
+		if err != nil {
+			return nil, err
+		}
+		if rev.Status != nil {
+			info.ReadyInstances = int(rev.Status.DesiredReplicas)
+		}
+	}
+	return info, nil
+}
+
+// annotationBool parses a boolean annotation, returning def if it is not set or not
+// a boolean.
+func annotationBool(annotations map[string]string, key string, def bool) bool {
+	b, err := strconv.ParseBool(annotations[key])
+	if err != nil {
+		return def
+	}
+	return b
+}
@@ -0,0 +1,114 @@
+// Package apiv2 implements reading and updating Cloud Run service scaling through
+// the Cloud Run Admin API v2 (run.googleapis.com/v2).
+package apiv2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"google.golang.org/api/run/v2"
+)
+
+// Doer sends a request to the Cloud Run API. The caller is responsible for closing
+// the response body.
+type Doer func(ctx context.Context, method, url string, body []byte) (*http.Response, error)
+
+// StatusError is returned when the API responds with an unexpected status code.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API response code: %d: %s", e.StatusCode, e.Body)
+}
+
+func newStatusError(resp *http.Response) *StatusError {
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+	return &StatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(b))}
+}
+
+// ServiceURL returns the v2 resource URL of a service.
+func ServiceURL(project, region, service string) string {
+	return fmt.Sprintf("https://run.googleapis.com/v2/projects/%s/locations/%s/services/%s",
+		project, region, service)
+}
+
+// GetService fetches a service.
+func GetService(ctx context.Context, do Doer, url string) (*run.GoogleCloudRunV2Service, error) {
+	resp, err := do(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp)
+	}
+
+	var svc run.GoogleCloudRunV2Service
+	err = json.NewDecoder(resp.Body).Decode(&svc)
+	if err != nil {
+		return nil, err
+	}
+	return &svc, nil
+}
+
+// Scaling returns the min and max instances of a service, 0 for any that are unset.
+func Scaling(svc *run.GoogleCloudRunV2Service) (min, max int) {
+	if svc.Scaling == nil {
+		return 0, 0
+	}
+	return int(svc.Scaling.MinInstanceCount), int(svc.Scaling.MaxInstanceCount)
+}
+
+// scalingPatch is the request body of UpdateScaling. Unlike the generated API types,
+// its counts are sent even when 0; only a max that is not given is left out.
+type scalingPatch struct {
+	Scaling struct {
+		MinInstanceCount int64  `json:"minInstanceCount"`
+		MaxInstanceCount *int64 `json:"maxInstanceCount,omitempty"`
+	} `json:"scaling"`
+}
+
+// ScalingPatch returns the request body UpdateScaling sends. A nil max is left out.
+func ScalingPatch(min int, max *int) ([]byte, error) {
+	var patch scalingPatch
+	patch.Scaling.MinInstanceCount = int64(min)
+	if max != nil {
+		n := int64(*max)
+		patch.Scaling.MaxInstanceCount = &n
+	}
+	return json.Marshal(patch)
+}
+
+// UpdateScaling patches only the scaling of a service. A nil max leaves the service
+// without max instances, removing any it has. Cloud Run creates the new revision
+// asynchronously; the returned operation tracks its progress.
+func UpdateScaling(ctx context.Context, do Doer, url string, min int, max *int) (*run.GoogleLongrunningOperation, error) {
+	b, err := ScalingPatch(min, max)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := do(ctx, http.MethodPatch, url+"?updateMask=scaling", b)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp)
+	}
+
+	var op run.GoogleLongrunningOperation
+	err = json.NewDecoder(resp.Body).Decode(&op)
+	if err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
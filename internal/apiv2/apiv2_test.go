@@ -0,0 +1,28 @@
+package apiv2
+
+import "testing"
+
+func TestScalingPatch(t *testing.T) {
+	zero, ten := 0, 10
+	tests := []struct {
+		name string
+		min  int
+		max  *int
+		want string
+	}{
+		{"zeros sent", 0, &zero, `{"scaling":{"minInstanceCount":0,"maxInstanceCount":0}}`},
+		{"max", 2, &ten, `{"scaling":{"minInstanceCount":2,"maxInstanceCount":10}}`},
+		{"no max", 2, nil, `{"scaling":{"minInstanceCount":2}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ScalingPatch(tt.min, tt.max)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("ScalingPatch = %s, want %s", b, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package scale
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/api/run/v1"
+)
+
+// ScaleJob sets the task count and parallelism of a Cloud Run Job. The job is
+// chosen with WithJob, falling back to the CLOUD_RUN_JOB environment variable that
+// Cloud Run sets for job executions. A parallelism of 0 lets Cloud Run run as many
+// tasks at once as it can. No update is made if the job already has these values.
+func ScaleJob(ctx context.Context, taskCount, parallelism int, opts ...ScaleOption) error {
+	if taskCount < 1 {
+		return &ConfigError{Field: "taskCount", Message: "must be at least 1"}
+	}
+	if parallelism < 0 {
+		return &ConfigError{Field: "parallelism", Message: "must not be negative"}
+	}
+
+	opts = withOptions(opts, contextOptions(ctx)...)
+	cfg := newConfig(opts)
+	c, err := newClient(ctx, &cfg, opts)
+	if err != nil {
+		return err
+	}
+	if cfg.Job == "" {
+		return &ConfigError{Field: "Job", Message: "not set; use WithJob or set CLOUD_RUN_JOB"}
+	}
+
+	url := fmt.Sprintf(
+		"https://%s-run.googleapis.com/apis/run.googleapis.com/v1/namespaces/%s/jobs/%s",
+		cfg.Region, c.project, cfg.Job)
+
+	resp, err := cfg.call(ctx, c.httpClient, "run-scaler/get-job", c.project, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("get job %s: %w", cfg.Job, newAPIError(resp))
+	}
+
+	var job run.Job
+	err = json.NewDecoder(resp.Body).Decode(&job)
+	if err != nil {
+		return err
+	}
+
+	// every job has an execution template; without one there is nothing to update
+	if job.Spec == nil || job.Spec.Template == nil || job.Spec.Template.Spec == nil {
+		return fmt.Errorf("get job %s: response has no execution template", cfg.Job)
+	}
+	// spec points into job, so the update below sends the job exactly as read with
+	// only these two fields changed
+	spec := job.Spec.Template.Spec
+
+	// noop if new values are same as current
+	if spec.TaskCount == int64(taskCount) && spec.Parallelism == int64(parallelism) {
+		cfg.log(ctx, "scale job noop: job already at requested values",
+			"project", c.project, "job", cfg.Job, "region", cfg.Region)
+		return nil
+	}
+	spec.TaskCount = int64(taskCount)
+	spec.Parallelism = int64(parallelism)
+
+	b, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	if cfg.DryRun {
+		return &DryRunResult{json: b}
+	}
+
+	// the v1 API has no partial update for jobs, so the whole job is replaced
+	// the same way services are
+	updateResp, err := cfg.call(ctx, c.httpClient, "run-scaler/update-job", c.project, http.MethodPut, url, b)
+	if err != nil {
+		return err
+	}
+	defer updateResp.Body.Close()
+
+	if updateResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("update job %s: %w", cfg.Job, newAPIError(updateResp))
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package scale
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ServiceScalingInfo is the current scaling of a service, as reported by ListScaling.
+type ServiceScalingInfo struct {
+	Service, Region, Project string
+	Min, Max                 int
+	// Concurrency is the revision's container concurrency, 0 if not set or read
+	// through APIv2.
+	Concurrency int
+}
+
+// ListScaling reads the current scaling of each of services concurrently, running at
+// most WithConcurrency reads at once. Services that could be read are returned in
+// the order given, alongside an error joining a ServiceScaleError for each that could
+// not; check both.
+func ListScaling(ctx context.Context, services []string, opts ...ScaleOption) ([]ServiceScalingInfo, error) {
+	c, err := NewClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	_, cfg := c.config(ctx)
+	parallelism := cfg.Parallelism
+	if parallelism <= 0 {
+		parallelism = defaultParallelism
+	}
+
+	var (
+		infos = make([]*ServiceScalingInfo, len(services))
+		errs  = make([]error, len(services))
+		wg    sync.WaitGroup
+		sem   = make(chan struct{}, parallelism)
+	)
+	for i, service := range services {
+		wg.Add(1)
+		go func(i int, service string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			info, err := c.scalingInfo(ctx, service)
+			if err != nil {
+				errs[i] = ServiceScaleError{Service: service, Err: err}
+				return
+			}
+			infos[i] = info
+		}(i, service)
+	}
+	wg.Wait()
+
+	result := make([]ServiceScalingInfo, 0, len(services))
+	for _, info := range infos {
+		if info != nil {
+			result = append(result, *info)
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// scalingInfo reads the current scaling of service.
+func (c *Client) scalingInfo(ctx context.Context, service string) (*ServiceScalingInfo, error) {
+	c, cfg := c.config(ctx, WithService(service))
+	info := &ServiceScalingInfo{Service: service, Region: cfg.Region, Project: c.project}
+	if cfg.APIVersion == APIv2 {
+		// apiv2 only reads min and max, so Concurrency is left at 0
+		var err error
+		if info.Min, info.Max, err = c.getScalingV2(ctx, cfg); err != nil {
+			return nil, err
+		}
+		return info, nil
+	}
+	svc, err := c.get(ctx, &cfg)
+	if err != nil {
+		return nil, err
+	}
+	if info.Min, info.Max, err = currentScaling(svc); err != nil {
+		return nil, err
+	}
+	info.Concurrency = int(svc.Spec.Template.Spec.ContainerConcurrency)
+	return info, nil
+}
@@ -0,0 +1,238 @@
+package scale
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrScaleInProgress is returned by Scale when another caller holds the lock for the
+// service, e.g. because two Cloud Scheduler jobs fired at the same time.
+var ErrScaleInProgress = errors.New("another scale operation is in progress for this service")
+
+// defaultLockTTL is how long a lock is honoured if its holder never releases it.
+const defaultLockTTL = 5 * time.Minute
+
+// LockProvider is a distributed lock that stops concurrent Scale calls on the same
+// service from both creating a revision. Acquire returns ErrScaleInProgress if the
+// lock is held and has not expired. Both are given the service as
+// project/region/service, so that services of the same name in different projects or
+// regions are locked separately.
+type LockProvider interface {
+	Acquire(ctx context.Context, service string) error
+	Release(ctx context.Context, service string) error
+}
+
+// WithLock makes Scale hold a lock, stored as an object in the given Cloud Storage
+// bucket, while it reads and updates the service. If the lock is held Scale returns
+// ErrScaleInProgress. Locks left behind by crashed processes expire after 5 minutes.
+// The caller needs permission to create, read and delete objects in the bucket.
+func WithLock(lockBucketName string) ScaleOption {
+	return func(c *Config) {
+		c.lockBucket = lockBucketName
+	}
+}
+
+// WithLockProvider makes Scale hold the given lock while it reads and updates the service.
+func WithLockProvider(p LockProvider) ScaleOption {
+	return func(c *Config) {
+		c.lockProvider = p
+	}
+}
+
+// lock returns the LockProvider configured in cfg, or nil.
+func (c *Client) lock(cfg *Config) LockProvider {
+	if cfg.lockProvider != nil {
+		return cfg.lockProvider
+	}
+	if cfg.lockBucket != "" {
+		return &gcsLock{
+			httpClient: c.httpClient,
+			bucket:     cfg.lockBucket,
+			prefix:     "run-scaler",
+			ttl:        defaultLockTTL,
+		}
+	}
+	return nil
+}
+
+// gcsLock is a LockProvider backed by Cloud Storage. Creating the lock object is
+// conditional on it not existing, which Cloud Storage guarantees atomically; deleting
+// it on release is conditional on it being the generation this lock created, so that
+// a lock taken over after expiring is not released by its previous holder.
+type gcsLock struct {
+	httpClient *http.Client
+	bucket     string
+	prefix     string
+	ttl        time.Duration
+
+	mu sync.Mutex
+	// held is the generation of the lock object created for each service
+	held map[string]string
+}
+
+func (l *gcsLock) objectURL(service string) string {
+	return fmt.Sprintf("https://storage.googleapis.com/storage/v1/b/%s/o/%s",
+		url.PathEscape(l.bucket), url.PathEscape(path.Join(l.prefix, service)))
+}
+
+func (l *gcsLock) Acquire(ctx context.Context, service string) error {
+	generation, err := l.acquire(ctx, service)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held == nil {
+		l.held = make(map[string]string)
+	}
+	l.held[service] = generation
+	return nil
+}
+
+// acquire creates the lock object, taking it over if it expired, and returns its
+// generation.
+func (l *gcsLock) acquire(ctx context.Context, service string) (string, error) {
+	generation, err := l.create(ctx, service)
+	if !errors.Is(err, ErrScaleInProgress) {
+		return generation, err
+	}
+
+	// the lock is held; take it over if its holder let it expire
+	obj, err := l.stat(ctx, service)
+	if err != nil {
+		return "", err
+	}
+	if obj == nil {
+		// released in the meantime
+		return l.create(ctx, service)
+	}
+	created, err := time.Parse(time.RFC3339, obj.TimeCreated)
+	if err != nil {
+		return "", fmt.Errorf("lock object: invalid timeCreated %q: %w", obj.TimeCreated, err)
+	}
+	if time.Since(created) < l.ttl {
+		return "", ErrScaleInProgress
+	}
+	if err := l.delete(ctx, service, obj.Generation); err != nil {
+		if errors.Is(err, errLockTakenOver) {
+			// someone else took over the expired lock first
+			return "", ErrScaleInProgress
+		}
+		return "", err
+	}
+	return l.create(ctx, service)
+}
+
+// Release deletes the lock object if it is still the one Acquire created. If the lock
+// expired and was taken over in the meantime, it is left to its new holder.
+func (l *gcsLock) Release(ctx context.Context, service string) error {
+	l.mu.Lock()
+	generation, ok := l.held[service]
+	delete(l.held, service)
+	l.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	err := l.delete(ctx, service, generation)
+	if errors.Is(err, errLockTakenOver) {
+		return nil
+	}
+	return err
+}
+
+// errLockTakenOver is returned by delete when the lock object is no longer at the
+// generation given.
+var errLockTakenOver = errors.New("lock taken over")
+
+// create writes the lock object, failing with ErrScaleInProgress if it exists, and
+// returns its generation.
+func (l *gcsLock) create(ctx context.Context, service string) (string, error) {
+	u := fmt.Sprintf("https://storage.googleapis.com/upload/storage/v1/b/%s/o?uploadType=media&ifGenerationMatch=0&name=%s",
+		url.PathEscape(l.bucket), url.QueryEscape(path.Join(l.prefix, service)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(time.Now().UTC().Format(time.RFC3339)))
+	if err != nil {
+		return "", err
+	}
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusPreconditionFailed:
+		return "", ErrScaleInProgress
+	default:
+		return "", fmt.Errorf("lock: %w", newAPIError(resp))
+	}
+	var obj gcsObject
+	if err := json.NewDecoder(resp.Body).Decode(&obj); err != nil {
+		return "", fmt.Errorf("lock: %w", err)
+	}
+	if obj.Generation == "" {
+		return "", errors.New("lock: created object has no generation")
+	}
+	return obj.Generation, nil
+}
+
+type gcsObject struct {
+	Generation  string `json:"generation"`
+	TimeCreated string `json:"timeCreated"`
+}
+
+// stat returns the lock object's metadata, or nil if it does not exist.
+func (l *gcsLock) stat(ctx context.Context, service string) (*gcsObject, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.objectURL(service), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("lock: %w", newAPIError(resp))
+	}
+	var obj gcsObject
+	if err := json.NewDecoder(resp.Body).Decode(&obj); err != nil {
+		return nil, err
+	}
+	return &obj, nil
+}
+
+// delete removes the lock object, only if it is still at generation, returning
+// errLockTakenOver if not.
+func (l *gcsLock) delete(ctx context.Context, service, generation string) error {
+	u := l.objectURL(service) + "?ifGenerationMatch=" + url.QueryEscape(generation)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	case http.StatusPreconditionFailed:
+		return errLockTakenOver
+	}
+	return fmt.Errorf("lock: %w", newAPIError(resp))
+}
@@ -0,0 +1,44 @@
+package scale
+
+import "sync/atomic"
+
+// globalMaxCap is the cap set with SetGlobalMaxCap, 0 if none.
+var globalMaxCap atomic.Int64
+
+// SetGlobalMaxCap makes every Scale in the process refuse, with a *ValidationError,
+// any max instances above n, e.g. set once in main as a hard bound on cost. Unlike
+// WithMaxCap it cannot be raised by an option, only lowered further by WithMaxCap,
+// and lifted for a single call with WithBypassMaxCap. n of zero or less removes the
+// cap.
+func SetGlobalMaxCap(n int) {
+	if n < 0 {
+		n = 0
+	}
+	globalMaxCap.Store(int64(n))
+}
+
+// CapBypassToken authorizes WithBypassMaxCap. Create one with NewCapBypassToken; the
+// zero value authorizes nothing.
+type CapBypassToken struct {
+	reason string
+}
+
+// NewCapBypassToken returns a token for WithBypassMaxCap. reason, e.g. an incident
+// ID, is required and logged with every call bypassing the cap (see WithLogger), so
+// that a bypass is always deliberate and accounted for.
+func NewCapBypassToken(reason string) CapBypassToken {
+	return CapBypassToken{reason: reason}
+}
+
+// WithBypassMaxCap lifts the cap set with SetGlobalMaxCap for this call, for
+// emergencies. The cap from WithMaxCap, or DefaultMaxCap, still applies. A token
+// without a reason is a *ConfigError.
+func WithBypassMaxCap(token CapBypassToken) ScaleOption {
+	return func(c *Config) {
+		if token.reason == "" {
+			c.err = &ConfigError{Field: "CapBypassToken", Message: "a reason is required to bypass the global max cap"}
+			return
+		}
+		c.capBypass = &token
+	}
+}
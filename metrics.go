@@ -0,0 +1,88 @@
+package scale
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "run_scaler"
+
+// metrics holds the Prometheus collectors registered by WithPrometheusRegisterer.
+// A nil *metrics records nothing.
+type metrics struct {
+	scaleCalls  *prometheus.CounterVec
+	noops       *prometheus.CounterVec
+	apiDuration *prometheus.HistogramVec
+}
+
+func newMetrics(reg prometheus.Registerer) (*metrics, error) {
+	m := &metrics{
+		scaleCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "scale_calls_total",
+			Help:      "Number of scale operations by result.",
+		}, []string{"service", "region", "result"}),
+		noops: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "noop_total",
+			Help:      "Number of scale operations that found the service already at the requested values.",
+		}, []string{"service", "region"}),
+		apiDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "api_duration_seconds",
+			Help:      "Duration of calls to the Cloud Run API.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"service", "region", "call"}),
+	}
+	var err error
+	m.scaleCalls, err = register(reg, m.scaleCalls)
+	if err != nil {
+		return nil, err
+	}
+	m.noops, err = register(reg, m.noops)
+	if err != nil {
+		return nil, err
+	}
+	m.apiDuration, err = register(reg, m.apiDuration)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// register registers c with reg, returning the already registered collector if
+// an earlier call registered the same metric.
+func register[C prometheus.Collector](reg prometheus.Registerer, c C) (C, error) {
+	err := reg.Register(c)
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(C); ok {
+			return existing, nil
+		}
+	}
+	return c, err
+}
+
+func (m *metrics) observeScale(cfg *Config, result *ScaleResult, err error) {
+	if m == nil {
+		return
+	}
+	outcome := "success"
+	switch {
+	case err != nil:
+		outcome = "error"
+	case result != nil && result.Noop:
+		outcome = "noop"
+		m.noops.WithLabelValues(cfg.Service, cfg.Region).Inc()
+	}
+	m.scaleCalls.WithLabelValues(cfg.Service, cfg.Region, outcome).Inc()
+}
+
+func (m *metrics) observeCall(cfg *Config, call string, start time.Time) {
+	if m == nil {
+		return
+	}
+	m.apiDuration.WithLabelValues(cfg.Service, cfg.Region, call).Observe(time.Since(start).Seconds())
+}
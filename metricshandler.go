@@ -0,0 +1,76 @@
+package scale
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+)
+
+// defaultMetricsEvents is how many events NewMetricsHandler reports unless the
+// request asks for another number.
+const defaultMetricsEvents = 100
+
+// scaleOps and noops count the successful Scale calls made by this process, noops
+// included in scaleOps.
+var scaleOps, noops atomic.Int64
+
+type metricsResponse struct {
+	Service              string       `json:"service"`
+	Project              string       `json:"project"`
+	Region               string       `json:"region"`
+	Min                  int          `json:"min"`
+	Max                  int          `json:"max"`
+	TotalScaleOperations int64        `json:"total_scale_operations"`
+	TotalNoops           int64        `json:"total_noops"`
+	Events               []ScaleEvent `json:"events"`
+}
+
+// NewMetricsHandler returns a handler reporting scaling telemetry as JSON, for teams
+// that want a quick scrape rather than Prometheus (see WithPrometheusRegisterer): the
+// service's current min and max instances, the number of successful Scale calls made
+// by this process since it started and how many of them were noops, and the
+// service's most recent events in store, newest first. 100 events are reported
+// unless the request asks for another number with ?limit=N. store may be nil to
+// leave events out.
+func NewMetricsHandler(store HistoryStore, opts ...ScaleOption) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := defaultMetricsEvents
+		if r.URL.Query().Has("limit") {
+			n, err := queryInt(r.URL.Query(), "limit")
+			if err != nil || n < 1 {
+				writeError(w, http.StatusBadRequest, errors.New("invalid limit: must be a positive integer"))
+				return
+			}
+			limit = n
+		}
+
+		ctx := r.Context()
+		c, err := NewClient(ctx, opts...)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		c, cfg := c.config(ctx)
+		resp := metricsResponse{
+			Service:              cfg.Service,
+			Project:              c.project,
+			Region:               cfg.Region,
+			TotalScaleOperations: scaleOps.Load(),
+			TotalNoops:           noops.Load(),
+			Events:               []ScaleEvent{},
+		}
+		if resp.Min, resp.Max, err = c.GetScaling(ctx); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if store != nil {
+			events, err := store.List(ctx, cfg.Service, limit)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err)
+				return
+			}
+			resp.Events = append(resp.Events, events...)
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,695 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/compute/metadata"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/oauth2"
+)
+
+const (
+	defaultRegion           = "us-central1"
+	defaultKnativeNamespace = "default"
+	defaultConflictRetries  = 3
+)
+
+// DefaultMaxCap is the highest max instances Scale accepts unless WithMaxCap sets
+// another cap, as a guard against runaway values from a buggy caller.
+const DefaultMaxCap = 1000
+
+// Config holds every parameter that can be set on a call to Scale.
+type Config struct {
+	// Min and Max correspond to Cloud Run's min and max instances.
+	Min, Max int
+	// MinFloor and MaxCeiling bound the values computed by the relative scaling
+	// functions such as ScaleMaxBy. A MaxCeiling of zero means no ceiling.
+	MinFloor, MaxCeiling int
+	// Concurrency is the target number of concurrent requests per instance.
+	// Zero leaves the service's current setting untouched.
+	Concurrency int
+	// CPU is the CPU limit in millicores and Memory the memory limit in MiB of the
+	// serving container. Zero leaves the current limit untouched.
+	CPU, Memory int
+	// StartupCPUBoost, if set, enables or disables startup CPU boost.
+	StartupCPUBoost *bool
+	// SessionAffinity, if set, enables or disables session affinity.
+	SessionAffinity *bool
+	// ExecutionEnvironment, if set, is the execution environment of new revisions.
+	ExecutionEnvironment ExecutionEnvironment
+	// Ingress, if set, is the traffic the service accepts.
+	Ingress IngressSetting
+	// VPCConnector, if set, is the Serverless VPC Access connector of new revisions,
+	// and VPCEgress, if set, the traffic routed through it.
+	VPCConnector, VPCEgress string
+	// TrafficPercent, if set, is the share of traffic sent to the new revision,
+	// the rest staying on the previous one.
+	TrafficPercent *int
+	// Region is the Cloud Run region the service is deployed in.
+	Region string
+	// Project is the ID of the Google Cloud project that owns the service.
+	Project string
+	// Service is the name of the Cloud Run service to scale.
+	Service string
+	// RevisionSuffix, if set, names the new revision <service>-<suffix> instead of
+	// letting Cloud Run generate a name.
+	RevisionSuffix string
+	// Revision is the name of the revision Rollback restores.
+	Revision string
+	// Job is the name of the Cloud Run Job scaled by ScaleJob.
+	Job string
+	// AnnotationMerge makes Scale re-read the service just before updating it.
+	AnnotationMerge bool
+	// Parallelism is the number of services ScaleAll scales at once.
+	Parallelism int
+	// ImpersonateServiceAccount, if set, is the email of a service account whose
+	// credentials are used instead of the caller's own.
+	ImpersonateServiceAccount string
+	// KnativeEndpoint, if set, replaces the Cloud Run Admin API base URL.
+	KnativeEndpoint string
+	// APIVersion is the Cloud Run Admin API version used.
+	APIVersion APIVersion
+	// AutoCorrect makes Watch scale the service back to the desired values on drift.
+	AutoCorrect bool
+	// ProjectList is the projects ScaleAll scales its targets in.
+	ProjectList []string
+	// ProjectImpersonation maps projects in ProjectList to the service account to
+	// impersonate in them.
+	ProjectImpersonation map[string]string
+	// Initiator is who or what triggered the scaling, reported to audit loggers.
+	Initiator string
+	// JSONResponse makes handlers write a JSON body describing the result.
+	JSONResponse bool
+	// DryRun skips the update call and returns the proposed service as a *DryRunResult.
+	DryRun bool
+
+	retry           retryPolicy
+	maxCapValue     int
+	capBypass       *CapBypassToken
+	conflictRetries int
+	logger          *slog.Logger
+
+	httpClient  *http.Client
+	httpTimeout time.Duration
+	userAgent   string
+	// requestHeaders are added to every call to the API
+	requestHeaders http.Header
+	// credentialsFile, credentialsJSON and tokenSource replace Application Default Credentials
+	credentialsFile string
+	credentialsJSON []byte
+	tokenSource     oauth2.TokenSource
+	tracerProvider  trace.TracerProvider
+	metrics         *metrics
+	authMiddleware  func(http.Handler) http.Handler
+	middleware      []func(http.Handler) http.Handler
+	auditLogger     func(context.Context, AuditEvent)
+	noopCallback    func(ctx context.Context, service string, min, max int)
+	historyStore    HistoryStore
+	notifier        func(ctx context.Context, event ScaleEvent) error
+	eventBus        *EventBus
+	breaker         *breaker
+	rateLimiter     *RateLimiter
+	lockBucket      string
+	lockProvider    LockProvider
+	maxRevisionAge  time.Duration
+	tag             string
+	// taskURL and taskServiceAccount set up the tasks created by ScheduleScale
+	taskURL            string
+	taskServiceAccount string
+	// perRevisionMax, if set, is written to the revision template in place of Max
+	perRevisionMax *int
+	// adjust, if set, sets Min and Max from the service's current values
+	adjust func(c *Config, oldMin, oldMax int) error
+	// keepMax, set by adjust, leaves the max instances of a service that has none
+	// unset rather than writing Max
+	keepMax bool
+	// clearMax, set by adjust, removes the max instances of the service, leaving
+	// Cloud Run's default, rather than writing Max
+	clearMax bool
+	// check, if set, refuses to scale a service from its current values by
+	// returning an error for them
+	check func(oldMin, oldMax int) error
+	err   error
+}
+
+// ScaleOption sets a parameter on a call to Scale.
+type ScaleOption func(*Config)
+
+// WithMin sets the minimum number of instances.
+func WithMin(n int) ScaleOption {
+	return func(c *Config) {
+		c.Min = n
+	}
+}
+
+// WithMax sets the maximum number of instances.
+func WithMax(n int) ScaleOption {
+	return func(c *Config) {
+		c.Max = n
+	}
+}
+
+// WithMaxCap makes Scale refuse, with a *ValidationError, any max instances above n
+// instead of DefaultMaxCap. This applies however the max was arrived at, including by
+// ScaleUp or a schedule.
+func WithMaxCap(n int) ScaleOption {
+	return func(c *Config) {
+		if n < 1 {
+			c.err = &ConfigError{Field: "MaxCap", Message: fmt.Sprintf("must be positive, got %d", n)}
+			return
+		}
+		c.maxCapValue = n
+	}
+}
+
+// maxCap returns the highest max instances allowed, including by the global cap
+// unless it is bypassed.
+func (c *Config) maxCap() int {
+	maxCap := DefaultMaxCap
+	if c.maxCapValue > 0 {
+		maxCap = c.maxCapValue
+	}
+	if global := int(globalMaxCap.Load()); global > 0 && global < maxCap && c.capBypass == nil {
+		maxCap = global
+	}
+	return maxCap
+}
+
+// WithMinFloor stops ScaleMinBy and the other relative scaling functions from setting
+// min instances below n.
+func WithMinFloor(n int) ScaleOption {
+	return func(c *Config) {
+		c.MinFloor = n
+	}
+}
+
+// WithMaxCeiling stops ScaleMaxBy and the other relative scaling functions from setting
+// max instances above n.
+func WithMaxCeiling(n int) ScaleOption {
+	return func(c *Config) {
+		c.MaxCeiling = n
+	}
+}
+
+// WithContainerConcurrency sets the target number of concurrent requests per instance.
+func WithContainerConcurrency(n int) ScaleOption {
+	return func(c *Config) {
+		c.Concurrency = n
+	}
+}
+
+// WithCPU sets the CPU limit of the serving container in millicores, e.g. 2000 for 2 CPUs.
+func WithCPU(millicores int) ScaleOption {
+	return func(c *Config) {
+		c.CPU = millicores
+	}
+}
+
+// WithMemory sets the memory limit of the serving container in MiB.
+func WithMemory(megabytes int) ScaleOption {
+	return func(c *Config) {
+		c.Memory = megabytes
+	}
+}
+
+// WithStartupCPUBoost enables or disables giving new instances extra CPU while they
+// start. Without it the service's current setting is kept.
+func WithStartupCPUBoost(enabled bool) ScaleOption {
+	return func(c *Config) {
+		c.StartupCPUBoost = &enabled
+	}
+}
+
+// WithPerRevisionMax sets the max instances of the revision Scale creates to n,
+// overriding the max given to Scale or WithMax (and any WithAdjust), without
+// touching the service-level max instances.
+//
+// Cloud Run reads max instances from two places. The revision-level limit is the
+// autoscaling.knative.dev/maxScale annotation on the revision template, which is the
+// only one Scale writes and applies to each revision separately. The service-level
+// limit is the run.googleapis.com/maxScale annotation on the service itself, set with
+// e.g. gcloud run services update --max. It caps instances across all of the
+// service's revisions together, so a revision never runs more than it allows,
+// whatever its own maxScale.
+func WithPerRevisionMax(n int) ScaleOption {
+	return func(c *Config) {
+		c.perRevisionMax = &n
+	}
+}
+
+// revisionMax returns the max instances to write to the revision template.
+func (c *Config) revisionMax() int {
+	if c.perRevisionMax != nil {
+		return *c.perRevisionMax
+	}
+	return c.Max
+}
+
+// keepsMax reports whether the revision template's max instances are left as they are.
+func (c *Config) keepsMax() bool {
+	return c.keepMax && c.perRevisionMax == nil
+}
+
+// clearsMax reports whether the revision template's max instances are removed.
+func (c *Config) clearsMax() bool {
+	return c.clearMax && c.perRevisionMax == nil
+}
+
+// withoutMax removes the service's max instances, leaving Cloud Run's default, e.g.
+// to restore a service that had none.
+func withoutMax() ScaleOption {
+	return func(c *Config) {
+		c.Max, c.clearMax = 0, true
+	}
+}
+
+// WithSessionAffinity enables or disables session affinity, which sends a client's
+// requests to the same instance where possible, in the same update as the scaling,
+// e.g. to keep sessions on their instances while scaling down. Without it the
+// service's current setting is kept.
+func WithSessionAffinity(enabled bool) ScaleOption {
+	return func(c *Config) {
+		c.SessionAffinity = &enabled
+	}
+}
+
+// ExecutionEnvironment is a Cloud Run execution environment.
+type ExecutionEnvironment string
+
+const (
+	Gen1 ExecutionEnvironment = "gen1"
+	Gen2 ExecutionEnvironment = "gen2"
+)
+
+// WithExecutionEnvironment runs new revisions in env. Without it the service's
+// current setting is kept, including when the annotation is not set at all.
+func WithExecutionEnvironment(env ExecutionEnvironment) ScaleOption {
+	return func(c *Config) {
+		if env != Gen1 && env != Gen2 {
+			c.err = &ConfigError{Field: "ExecutionEnvironment", Message: fmt.Sprintf("must be %s or %s, got %q", Gen1, Gen2, env)}
+			return
+		}
+		c.ExecutionEnvironment = env
+	}
+}
+
+// IngressSetting is the traffic a Cloud Run service accepts.
+type IngressSetting string
+
+const (
+	IngressAll                           IngressSetting = "all"
+	IngressInternal                      IngressSetting = "internal"
+	IngressInternalAndCloudLoadBalancing IngressSetting = "internal-and-cloud-load-balancing"
+)
+
+// WithIngress changes the traffic the service accepts in the same update as the
+// scaling, e.g. IngressInternal to stop serving external traffic while scaling down
+// to zero. Without it the service's current setting is kept.
+func WithIngress(ingress IngressSetting) ScaleOption {
+	return func(c *Config) {
+		switch ingress {
+		case IngressAll, IngressInternal, IngressInternalAndCloudLoadBalancing:
+		default:
+			c.err = &ConfigError{Field: "Ingress", Message: fmt.Sprintf("unknown setting %q", ingress)}
+			return
+		}
+		c.Ingress = ingress
+	}
+}
+
+// WithVPCConnector sets the Serverless VPC Access connector of new revisions, by name
+// or full resource name, and which of their outbound traffic goes through it:
+// "private-ranges-only" or "all-traffic". An empty egress keeps the current setting.
+func WithVPCConnector(connector, egress string) ScaleOption {
+	return func(c *Config) {
+		switch {
+		case connector == "":
+			c.err = &ConfigError{Field: "VPCConnector", Message: "connector must not be empty"}
+			return
+		case egress != "" && egress != "private-ranges-only" && egress != "all-traffic":
+			c.err = &ConfigError{Field: "VPCEgress", Message: fmt.Sprintf("must be private-ranges-only or all-traffic, got %q", egress)}
+			return
+		}
+		c.VPCConnector, c.VPCEgress = connector, egress
+	}
+}
+
+// WithTrafficPercent sends only percent of traffic to the revision Scale creates,
+// keeping the rest on the revision that was serving before, for canary or blue/green
+// rollouts. The split is set in spec.traffic of the same update that creates the
+// revision rather than by a second request afterwards: the v1 API has no PATCH for
+// services, and a follow-up update would leave a window in which the new revision
+// receives all traffic, or, if it failed, keep it that way. Nothing changes on a noop.
+func WithTrafficPercent(percent int) ScaleOption {
+	return func(c *Config) {
+		if percent < 0 || percent > 100 {
+			c.err = &ConfigError{Field: "TrafficPercent", Message: fmt.Sprintf("must be between 0 and 100, got %d", percent)}
+			return
+		}
+		c.TrafficPercent = &percent
+	}
+}
+
+// WithConflictRetries sets how many times Scale starts over from a fresh read of the
+// service when its update fails because the service was changed concurrently,
+// before returning ErrConcurrentModification. Relative changes such as ScaleUp are
+// recomputed from the fresh values. The default is 3; 0 disables retrying.
+func WithConflictRetries(n int) ScaleOption {
+	return func(c *Config) {
+		if n < 0 {
+			c.err = &ConfigError{Field: "ConflictRetries", Message: fmt.Sprintf("must not be negative, got %d", n)}
+			return
+		}
+		c.conflictRetries = n
+	}
+}
+
+// WithAnnotationMerge makes Scale re-read the service right before updating it and
+// set only the scaling keys it manages on that fresh copy, so annotations and other
+// changes made by someone else since the first read are left untouched. Changes made
+// after the re-read are still protected by the update failing with a conflict (see
+// WithConflictRetries).
+func WithAnnotationMerge() ScaleOption {
+	return func(c *Config) {
+		c.AnnotationMerge = true
+	}
+}
+
+// WithConcurrency sets how many services ScaleAll scales at once. Not to be confused
+// with WithContainerConcurrency, which sets the requests handled per instance.
+func WithConcurrency(n int) ScaleOption {
+	return func(c *Config) {
+		c.Parallelism = n
+	}
+}
+
+// WithRegion sets the Cloud Run region of the service being scaled e.g.
+// scale.Scale(ctx, scale.WithMin(1), scale.WithMax(10), scale.WithRegion("europe-west4"))
+// If not given, the region is read from K_REGION, then from the metadata server,
+// falling back to us-central1.
+func WithRegion(region string) ScaleOption {
+	return func(c *Config) {
+		c.Region = region
+	}
+}
+
+// WithService sets the name of the Cloud Run service being scaled, allowing one service
+// to scale another. If not given, the service name is read from K_SERVICE.
+func WithService(name string) ScaleOption {
+	return func(c *Config) {
+		c.Service = name
+	}
+}
+
+// WithServiceFromEnv sets the name of the service being scaled to the value of the
+// environment variable envVar, if it is set, instead of K_SERVICE, which only Cloud Run
+// sets. That lets code run locally or in CI reach a real service through the same
+// path it takes when deployed, e.g. with WithServiceFromEnv("SCALE_SERVICE") and
+//
+//	SCALE_SERVICE=my-svc go test ./...
+//
+// If envVar is unset or empty, the service name comes from the other options or
+// K_SERVICE as usual.
+func WithServiceFromEnv(envVar string) ScaleOption {
+	return func(c *Config) {
+		if name := os.Getenv(envVar); name != "" {
+			c.Service = name
+		}
+	}
+}
+
+// WithRevisionSuffix names the revision created by Scale <service>-<suffix> instead of
+// letting Cloud Run generate one. The full name must be at most 63 characters of
+// lowercase letters, digits and hyphens, starting with a letter and not ending with a
+// hyphen. Revision names must be unique, so the suffix needs to change on every call,
+// e.g. a timestamp.
+func WithRevisionSuffix(suffix string) ScaleOption {
+	return func(c *Config) {
+		c.RevisionSuffix = suffix
+	}
+}
+
+// WithRevision makes Rollback restore the scaling of the named revision instead of
+// the previous one.
+func WithRevision(name string) ScaleOption {
+	return func(c *Config) {
+		c.Revision = name
+	}
+}
+
+// WithJob sets the name of the Cloud Run Job scaled by ScaleJob.
+// If not given, the job name is read from CLOUD_RUN_JOB.
+func WithJob(name string) ScaleOption {
+	return func(c *Config) {
+		c.Job = name
+	}
+}
+
+// WithProject sets the ID of the project that owns the service being scaled.
+// If not given, the project is read from the metadata server, which is only available
+// when running on Google Cloud.
+func WithProject(id string) ScaleOption {
+	return func(c *Config) {
+		c.Project = id
+	}
+}
+
+// WithDryRun makes Scale skip the update call. Instead of applying the change, Scale
+// returns a *DryRunResult error holding the service that would have been submitted e.g.
+//
+//	var dry *scale.DryRunResult
+//	if _, err := scale.Scale(ctx, scale.WithMax(10), scale.WithDryRun()); errors.As(err, &dry) {
+//	    log.Printf("would apply: %s", dry.JSON())
+//	}
+//
+// If the service is already at the requested values Scale returns nil as usual.
+func WithDryRun() ScaleOption {
+	return func(c *Config) {
+		c.DryRun = true
+	}
+}
+
+// WithKnativeEndpoint points Scale at any Knative Serving installation that speaks the
+// serving.knative.dev/v1 API, e.g. Knative on GKE, instead of the Cloud Run Admin API.
+// baseURL is the API server address such as https://34.1.2.3. WithProject then sets the
+// Kubernetes namespace, which defaults to "default". Combine with WithHTTPClient to
+// authenticate against the cluster.
+func WithKnativeEndpoint(baseURL string) ScaleOption {
+	return func(c *Config) {
+		c.KnativeEndpoint = baseURL
+	}
+}
+
+// WithHTTPClient sets the client used to call the API instead of one built from
+// Application Default Credentials. The client is responsible for authentication.
+func WithHTTPClient(hc *http.Client) ScaleOption {
+	return func(c *Config) {
+		c.httpClient = hc
+	}
+}
+
+// WithHTTPTimeout bounds each individual call to the API, including reading its
+// response, to d. A call that times out fails without cancelling ctx, and is retried
+// like any other network timeout when WithRetry is set.
+func WithHTTPTimeout(d time.Duration) ScaleOption {
+	return func(c *Config) {
+		c.httpTimeout = d
+	}
+}
+
+// WithUserAgent prepends ua, e.g. "my-scaler/1.2.3", to the User-Agent of calls to
+// the API, so that they can be told apart in the Cloud Run audit logs.
+func WithUserAgent(ua string) ScaleOption {
+	return func(c *Config) {
+		c.userAgent = ua
+	}
+}
+
+// WithRequestHeader adds the header key: value to every call to the API, e.g.
+// X-Goog-Request-Reason to explain the change in the audit logs, or a header an API
+// gateway or proxy requires. Pass it several times for several headers; values for
+// the same key are all sent.
+func WithRequestHeader(key, value string) ScaleOption {
+	return func(c *Config) {
+		if c.requestHeaders == nil {
+			c.requestHeaders = make(http.Header)
+		}
+		c.requestHeaders.Add(key, value)
+	}
+}
+
+// WithAutoCorrect makes Watch scale the service back to the desired values whenever
+// it finds them changed.
+func WithAutoCorrect() ScaleOption {
+	return func(c *Config) {
+		c.AutoCorrect = true
+	}
+}
+
+// WithImpersonateServiceAccount makes calls to the Cloud Run API as the given service
+// account, e.g. one with permission to manage services in another project. The
+// caller's own credentials need roles/iam.serviceAccountTokenCreator on it.
+func WithImpersonateServiceAccount(email string) ScaleOption {
+	return func(c *Config) {
+		c.ImpersonateServiceAccount = email
+	}
+}
+
+// WithCredentialsFile authenticates with the credentials in the JSON file at path
+// instead of Application Default Credentials. Any file accepted by gcloud works,
+// e.g. a service account key or a Workload Identity Federation configuration.
+func WithCredentialsFile(path string) ScaleOption {
+	return func(c *Config) {
+		c.credentialsFile = path
+	}
+}
+
+// WithCredentialsJSON is like WithCredentialsFile with the file's contents.
+func WithCredentialsJSON(b []byte) ScaleOption {
+	return func(c *Config) {
+		c.credentialsJSON = b
+	}
+}
+
+// WithWorkloadIdentityTokenSource authenticates with tokens from ts, e.g. one
+// exchanging an AWS, Azure or GitHub Actions OIDC token through Workload Identity
+// Federation, for running outside Google Cloud. There is no metadata server there,
+// so set the project with WithProject.
+func WithWorkloadIdentityTokenSource(ts oauth2.TokenSource) ScaleOption {
+	return func(c *Config) {
+		c.tokenSource = ts
+	}
+}
+
+// WithJSONResponse makes NewHandler respond with a JSON body instead of only a
+// status code: {"ok":true,"noop":false,"revision":"<name>"} on success and
+// {"ok":false,"error":"<msg>"} with status 500 on failure.
+func WithJSONResponse() ScaleOption {
+	return func(c *Config) {
+		c.JSONResponse = true
+	}
+}
+
+// WithAuthMiddleware wraps the handler returned by NewDynamicHandler with mw, e.g. to
+// validate Cloud IAP headers or a JWT before any scaling happens.
+func WithAuthMiddleware(mw func(http.Handler) http.Handler) ScaleOption {
+	return func(c *Config) {
+		c.authMiddleware = mw
+	}
+}
+
+// WithMiddleware wraps the handlers returned by NewHandler and NewDynamicHandler in
+// mw, e.g. for logging or rate limiting. The first middleware is the outermost and
+// sees each request first. Repeated uses add to the chain.
+func WithMiddleware(mw ...func(http.Handler) http.Handler) ScaleOption {
+	return func(c *Config) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// WithRetry retries calls to the Cloud Run API that fail with a 429, 500, 502, 503 or 504
+// response or a transient network error. Each call is tried at most maxAttempts times,
+// sleeping a random duration of up to baseDelay * 2^n between attempts.
+func WithRetry(maxAttempts int, baseDelay time.Duration) ScaleOption {
+	return func(c *Config) {
+		c.retry = retryPolicy{maxAttempts: maxAttempts, baseDelay: baseDelay}
+	}
+}
+
+// WithLogger makes Scale log every scaling decision to l at Info level.
+// Without it, Scale does not log anything.
+func WithLogger(l *slog.Logger) ScaleOption {
+	return func(c *Config) {
+		c.logger = l
+	}
+}
+
+func (c *Config) log(ctx context.Context, msg string, args ...any) {
+	if c.logger != nil {
+		c.logger.InfoContext(ctx, msg, args...)
+	}
+}
+
+// WithTracerProvider makes Scale record the calls it makes to the Cloud Run API as
+// spans named run-scaler/get-service and run-scaler/update-service.
+func WithTracerProvider(tp trace.TracerProvider) ScaleOption {
+	return func(c *Config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithPrometheusRegisterer registers run_scaler_scale_calls_total, run_scaler_noop_total
+// and run_scaler_api_duration_seconds with reg and records every call to Scale in them.
+// The same registerer can safely be passed on every call.
+func WithPrometheusRegisterer(reg prometheus.Registerer) ScaleOption {
+	return func(c *Config) {
+		m, err := newMetrics(reg)
+		if err != nil {
+			c.err = err
+			return
+		}
+		c.metrics = m
+	}
+}
+
+func newConfig(opts []ScaleOption) Config {
+	c := Config{conflictRetries: defaultConflictRetries}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.Region == "" {
+		c.Region = os.Getenv("K_REGION")
+	}
+	if c.Region == "" {
+		c.Region = metadataRegion()
+	}
+	if c.Region == "" {
+		c.Region = defaultRegion
+	}
+	if c.Service == "" {
+		c.Service = os.Getenv("K_SERVICE")
+	}
+	if c.Job == "" {
+		c.Job = os.Getenv("CLOUD_RUN_JOB")
+	}
+	return c
+}
+
+// metadataTimeout bounds the region lookup, so that running without a metadata
+// server, e.g. locally, only costs this much once.
+const metadataTimeout = 500 * time.Millisecond
+
+var (
+	metadataRegionOnce  sync.Once
+	metadataRegionValue string
+)
+
+// metadataRegion returns the region the metadata server reports, or "" if there is
+// none. The result is looked up once per process.
+func metadataRegion() string {
+	metadataRegionOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), metadataTimeout)
+		defer cancel()
+		// of the form projects/123456789/regions/us-central1
+		region, err := metadata.GetWithContext(ctx, "instance/region")
+		if err != nil {
+			return
+		}
+		metadataRegionValue = region[strings.LastIndex(region, "/")+1:]
+	})
+	return metadataRegionValue
+}
+
+// withOptions returns a new slice with extra appended to opts, so that extra take
+// precedence without modifying the caller's slice.
+func withOptions(opts []ScaleOption, extra ...ScaleOption) []ScaleOption {
+	return append(append(make([]ScaleOption, 0, len(opts)+len(extra)), opts...), extra...)
+}
@@ -0,0 +1,40 @@
+package scale
+
+import (
+	"context"
+	"math"
+)
+
+// Policy turns a load signal into min and max instances e.g.
+//
+//	p := scale.Policy{MinInstances: 2, MaxInstances: 50, ScaleUpThreshold: 0.8, ScaleDownThreshold: 0.2}
+//	err := p.Apply(ctx, cpuUtilization)
+//
+// Max instances is always MaxInstances. Min instances is MinInstances while the load
+// is at or below ScaleDownThreshold, MaxInstances once it reaches ScaleUpThreshold, and
+// rises linearly between the two in between, so warm capacity grows with the load.
+// Load is typically a utilization between 0 and 1, but any unit works as long as the
+// thresholds use the same one.
+type Policy struct {
+	MinInstances, MaxInstances           int
+	ScaleUpThreshold, ScaleDownThreshold float64
+}
+
+// Evaluate returns the min and max instances for currentLoad.
+func (p Policy) Evaluate(currentLoad float64) (newMin, newMax int) {
+	switch {
+	case currentLoad >= p.ScaleUpThreshold:
+		return p.MaxInstances, p.MaxInstances
+	case currentLoad <= p.ScaleDownThreshold:
+		return p.MinInstances, p.MaxInstances
+	}
+	frac := (currentLoad - p.ScaleDownThreshold) / (p.ScaleUpThreshold - p.ScaleDownThreshold)
+	newMin = p.MinInstances + int(math.Ceil(frac*float64(p.MaxInstances-p.MinInstances)))
+	return newMin, p.MaxInstances
+}
+
+// Apply scales the service to the values Evaluate returns for currentLoad.
+func (p Policy) Apply(ctx context.Context, currentLoad float64, opts ...ScaleOption) error {
+	min, max := p.Evaluate(currentLoad)
+	return ScaleMinMax(ctx, min, max, opts...)
+}
@@ -0,0 +1,129 @@
+package scale
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// ScaleWithPrewarm scales the service to targetMax and at least one min instance right
+// away, then to targetMin once prewarmDuration has passed, so that a service that
+// normally scales to zero is warm when traffic arrives. Only the first update is
+// waited for; its error is returned.
+//
+// The second update runs in the background under ctx, so ctx must stay alive for
+// prewarmDuration. If it is cancelled or its deadline passes first, the second update
+// is dropped and the service stays at min max(1, targetMin) until scaled again; with
+// a request's context, pass context.WithoutCancel(r.Context()) instead. Neither a
+// dropped nor a failed second update is returned to the caller; both are logged
+// (see WithLogger).
+func ScaleWithPrewarm(ctx context.Context, targetMin, targetMax int, prewarmDuration time.Duration, opts ...ScaleOption) error {
+	prewarmMin := max(1, targetMin)
+	if err := ScaleMinMax(ctx, prewarmMin, targetMax, opts...); err != nil {
+		return err
+	}
+	if prewarmMin == targetMin {
+		return nil
+	}
+
+	cfg := newConfig(withOptions(opts, contextOptions(ctx)...))
+	go func() {
+		t := time.NewTimer(prewarmDuration)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			cfg.log(ctx, "prewarm: context done before scaling down, service left at prewarm values",
+				"service", cfg.Service, "min", prewarmMin, "max", targetMax, "error", ctx.Err())
+			return
+		case <-t.C:
+		}
+		if err := ScaleMinMax(ctx, targetMin, targetMax, opts...); err != nil {
+			cfg.log(ctx, "prewarm: scaling to target failed", "service", cfg.Service, "min", targetMin, "max", targetMax, "error", err)
+		}
+	}()
+	return nil
+}
+
+type prewarmResponse struct {
+	scaleResponse
+	OriginalMax originalMax `json:"original_max"`
+	ResetAt     time.Time   `json:"reset_at"`
+}
+
+// originalMax is the max instances a service had before prewarming, 0 if it had none.
+type originalMax int
+
+// MarshalJSON writes a max that was not set as "unset" rather than 0, which would read
+// as a service allowed no instances.
+func (m originalMax) MarshalJSON() ([]byte, error) {
+	if m == 0 {
+		return []byte(`"unset"`), nil
+	}
+	return json.Marshal(int(m))
+}
+
+// NewPrewarmHandler returns a handler that sets the service's max instances to
+// targetMax, keeping its min, and sets it back to the original max once
+// warmupDuration has passed, e.g. called by Cloud Scheduler ahead of a launch.
+// The response tells operators the service is in a temporary state, e.g.
+// {"ok":true,"noop":false,"revision":"...","original_max":10,"reset_at":"..."}.
+// A service that had no max instances has it removed again on reset, and its
+// original_max is reported as "unset".
+//
+// The reset runs in a goroutine detached from the request, so it happens even
+// though the request has finished, but only if the process is still running then:
+// on Cloud Run, the instance must have CPU allocated outside of requests and not be
+// shut down in the meantime. Failures of the reset are logged (see WithLogger).
+func NewPrewarmHandler(targetMax int, warmupDuration time.Duration, opts ...ScaleOption) http.HandlerFunc {
+	cfg := newConfig(opts)
+	return cfg.wrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, err := Scale(r.Context(), withOptions(opts, withMaxOnly(targetMax))...)
+		if err != nil {
+			writeError(w, errorStatus(err), err)
+			return
+		}
+		resetAt := time.Now().Add(warmupDuration)
+		oldMax := result.OldMax
+		if oldMax != targetMax {
+			// keep the request's values, e.g. ProjectFromContext, but not its deadline
+			ctx := context.WithoutCancel(r.Context())
+			reset := withMaxOnly(oldMax)
+			if oldMax == 0 {
+				reset = withMaxUnset()
+			}
+			go func() {
+				time.Sleep(time.Until(resetAt))
+				if _, err := Scale(ctx, withOptions(opts, reset)...); err != nil {
+					cfg.log(ctx, "prewarm: resetting max failed", "service", cfg.Service, "max", oldMax, "error", err)
+				}
+			}()
+		}
+		writeJSON(w, http.StatusOK, prewarmResponse{
+			scaleResponse: scaleResponse{OK: true, Noop: result.Noop, Revision: result.RevisionName},
+			OriginalMax:   originalMax(oldMax),
+			ResetAt:       resetAt,
+		})
+	})).ServeHTTP
+}
+
+// withMaxOnly sets max instances to max, leaving min as the service has it.
+func withMaxOnly(max int) ScaleOption {
+	return func(c *Config) {
+		c.adjust = func(c *Config, oldMin, _ int) error {
+			c.Min, c.Max = oldMin, max
+			return nil
+		}
+	}
+}
+
+// withMaxUnset removes the service's max instances, leaving min as the service has it.
+func withMaxUnset() ScaleOption {
+	return func(c *Config) {
+		withoutMax()(c)
+		c.adjust = func(c *Config, oldMin, _ int) error {
+			c.Min = oldMin
+			return nil
+		}
+	}
+}
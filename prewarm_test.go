@@ -0,0 +1,51 @@
+package scale_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	scale "github.com/darrenmcc/run-scaler"
+	"github.com/darrenmcc/run-scaler/scaletest"
+)
+
+func TestPrewarmHandlerUnsetMax(t *testing.T) {
+	srv, _ := scaletest.NewTestServer(t, newService(map[string]string{
+		"autoscaling.knative.dev/minScale": "1",
+	}))
+	h := scale.NewPrewarmHandler(50, 10*time.Millisecond, serverOptions(srv)...)
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodPost, "/prewarm", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	var resp struct {
+		OriginalMax any `json:"original_max"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.OriginalMax != "unset" {
+		t.Errorf("original_max = %v, want unset", resp.OriginalMax)
+	}
+
+	// wait for the reset to remove the max again
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		annotations := scaletest.Service(srv).Spec.Template.Metadata.Annotations
+		_, hasMax := annotations["autoscaling.knative.dev/maxScale"]
+		if !hasMax && len(scaletest.Requests(srv)) > 2 {
+			if got := annotations["autoscaling.knative.dev/minScale"]; got != "1" {
+				t.Errorf("minScale = %q after reset, want 1", got)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("max not removed after reset: %v", annotations)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,80 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/api/run/v1"
+)
+
+// WithMaxRevisionAge makes Scale delete the service's revisions older than d after
+// every update, so that frequent scaling does not pile up revisions. Revisions that
+// serve traffic, or are the latest created or ready, are always kept. Failures to
+// prune are logged (see WithLogger) and do not fail Scale. APIv2 ignores it.
+func WithMaxRevisionAge(d time.Duration) ScaleOption {
+	return func(c *Config) {
+		c.maxRevisionAge = d
+	}
+}
+
+// pruneRevisions deletes the revisions of svc, as returned by the update, that are
+// older than cfg's maximum age and not in use.
+func (c *Client) pruneRevisions(ctx context.Context, cfg *Config, svc *run.Service) {
+	revisions, err := c.listRevisions(ctx, cfg)
+	if err != nil {
+		cfg.log(ctx, "scale: listing revisions to prune failed", "service", cfg.Service, "error", err)
+		return
+	}
+	inUse := revisionsInUse(svc)
+	cutoff := time.Now().Add(-cfg.maxRevisionAge)
+	for _, rev := range revisions {
+		if rev.Metadata == nil || inUse[rev.Metadata.Name] {
+			continue
+		}
+		created, err := time.Parse(time.RFC3339, rev.Metadata.CreationTimestamp)
+		if err != nil || !created.Before(cutoff) {
+			continue
+		}
+		if err := c.deleteRevision(ctx, cfg, rev.Metadata.Name); err != nil {
+			cfg.log(ctx, "scale: pruning revision failed", "service", cfg.Service, "revision", rev.Metadata.Name, "error", err)
+			continue
+		}
+		cfg.log(ctx, "scale: pruned revision", "service", cfg.Service, "revision", rev.Metadata.Name)
+	}
+}
+
+// revisionsInUse returns the names of the revisions svc routes traffic to, in its
+// spec or status, and its latest created and ready revisions.
+func revisionsInUse(svc *run.Service) map[string]bool {
+	inUse := map[string]bool{createdRevision(svc): true}
+	if svc.Spec != nil {
+		for _, t := range svc.Spec.Traffic {
+			inUse[t.RevisionName] = true
+		}
+	}
+	if svc.Status != nil {
+		inUse[svc.Status.LatestCreatedRevisionName] = true
+		inUse[svc.Status.LatestReadyRevisionName] = true
+		for _, t := range svc.Status.Traffic {
+			inUse[t.RevisionName] = true
+		}
+	}
+	delete(inUse, "")
+	return inUse
+}
+
+func (c *Client) deleteRevision(ctx context.Context, cfg *Config, name string) error {
+	resp, err := cfg.call(ctx, c.httpClient, "run-scaler/delete-revision", c.project, http.MethodDelete, c.revisionsURL(cfg)+"/"+name, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNotFound:
+		return nil
+	}
+	return fmt.Errorf("delete revision %s: %w", name, newAPIError(resp))
+}
@@ -0,0 +1,74 @@
+package scale
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// pushEnvelope is the body of a Pub/Sub push delivery.
+type pushEnvelope struct {
+	Message *struct {
+		Data       string            `json:"data"`
+		MessageID  string            `json:"messageId"`
+		Attributes map[string]string `json:"attributes"`
+	} `json:"message"`
+	Subscription string `json:"subscription"`
+}
+
+// NewPubSubHandler returns a handler for Pub/Sub push subscriptions, e.g. fed by a
+// Cloud Scheduler job publishing to a topic, that scales the service to min and max
+// on every message. It responds 204 if the service was already at those values, 200
+// once scaled and 500 on failures that may pass, so Pub/Sub acks successes and
+// redelivers those failures. Messages that can never succeed, a malformed push
+// request or values refused by validation, are logged (see WithLogger) and acked
+// with 200, since Pub/Sub would otherwise redeliver them until they expire. With
+// WithJSONResponse every response, noops included, has a JSON body like NewHandler's.
+// Middleware set with WithMiddleware wraps the handler.
+func NewPubSubHandler(min, max int, opts ...ScaleOption) http.HandlerFunc {
+	opts = withOptions(opts, WithMin(min), WithMax(max))
+	cfg := newConfig(opts)
+	return cfg.wrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		drop := func(err error) {
+			cfg.log(ctx, "pubsub: dropping message that cannot succeed", "service", cfg.Service, "error", err)
+			if cfg.JSONResponse {
+				writeError(w, http.StatusOK, err)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}
+
+		var env pushEnvelope
+		if err := json.NewDecoder(r.Body).Decode(&env); err != nil {
+			drop(fmt.Errorf("invalid Pub/Sub push envelope: %w", err))
+			return
+		}
+		if env.Message == nil {
+			drop(errors.New("invalid Pub/Sub push envelope: missing message"))
+			return
+		}
+		if _, err := base64.StdEncoding.DecodeString(env.Message.Data); err != nil {
+			drop(fmt.Errorf("invalid Pub/Sub message data: %w", err))
+			return
+		}
+
+		result, err := Scale(ctx, opts...)
+		var (
+			invalid *ValidationError
+			config  *ConfigError
+		)
+		switch {
+		case errors.As(err, &invalid) || errors.As(err, &config):
+			drop(err)
+		case err != nil:
+			writeScaleResult(w, nil, err, cfg.JSONResponse)
+		case result.Noop && !cfg.JSONResponse:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeScaleResult(w, result, nil, cfg.JSONResponse)
+		}
+	})).ServeHTTP
+}
@@ -0,0 +1,56 @@
+package scale_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	scale "github.com/darrenmcc/run-scaler"
+	"github.com/darrenmcc/run-scaler/scaletest"
+)
+
+func TestPubSubHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max int
+		body     string
+		want     int
+		updated  bool
+	}{
+		{"scaled", 1, 10, `{"message":{"data":""}}`, http.StatusOK, true},
+		{"malformed envelope acked", 1, 10, `{"message":`, http.StatusOK, false},
+		{"missing message acked", 1, 10, `{}`, http.StatusOK, false},
+		{"invalid values acked", 5, 1, `{"message":{"data":""}}`, http.StatusOK, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _ := scaletest.NewTestServer(t, newService(nil))
+			var wrapped bool
+			mw := func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					wrapped = true
+					next.ServeHTTP(w, r)
+				})
+			}
+			h := scale.NewPubSubHandler(tt.min, tt.max, append(serverOptions(srv), scale.WithMiddleware(mw))...)
+
+			rec := httptest.NewRecorder()
+			h(rec, httptest.NewRequest(http.MethodPost, "/pubsub", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if !wrapped {
+				t.Error("middleware not called")
+			}
+			var updated bool
+			for _, r := range scaletest.Requests(srv) {
+				updated = updated || r.Method == http.MethodPut
+			}
+			if updated != tt.updated {
+				t.Errorf("service updated = %v, want %v", updated, tt.updated)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiting how often Scale calls the Cloud Run API,
+// e.g. to keep a caller stuck in a loop from exhausting the API quota or creating
+// revision after revision. Share one between all the calls it should limit.
+// A nil *RateLimiter allows every call.
+type RateLimiter struct {
+	rps   float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a RateLimiter allowing rps calls per second on average and
+// bursts of up to burst calls, starting full. A burst below 1 is treated as 1 and an
+// rps of zero or less allows every call.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{rps: rps, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// WithRateLimiter makes Scale wait for a token from rl before doing anything, rather
+// than failing, so that bursts of calls are spread out. If ctx is done, or its
+// deadline would pass before a token is available, Scale returns an error without
+// waiting any further.
+func WithRateLimiter(rl *RateLimiter) ScaleOption {
+	return func(c *Config) {
+		c.rateLimiter = rl
+	}
+}
+
+// Wait blocks until a call is allowed or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil || l.rps <= 0 {
+		return nil
+	}
+	delay := l.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		l.cancel()
+		return fmt.Errorf("rate limited: next call allowed in %v, after the context deadline", delay)
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token, going into debt if there is none, and returns how long
+// until the token taken is actually available.
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens += now.Sub(l.last).Seconds() * l.rps
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rps * float64(time.Second))
+}
+
+// cancel gives back a token taken by reserve but not used.
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens++
+}
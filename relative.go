@@ -0,0 +1,97 @@
+package scale
+
+import "context"
+
+// ScaleUp adds deltaMin and deltaMax to the service's current min and max instances.
+// The values are read and updated in a single read-modify-write; if the service is
+// changed by someone else in between, the delta is applied again to the new values
+// (see WithConflictRetries).
+// A service with no max instances set keeps none, so deltaMax must then be 0; anything
+// else is refused with a *ValidationError.
+func ScaleUp(ctx context.Context, deltaMin, deltaMax int, opts ...ScaleOption) error {
+	_, err := Scale(ctx, withOptions(opts, withAdjust(deltaMin, deltaMax))...)
+	return err
+}
+
+// ScaleDown subtracts deltaMin and deltaMax from the service's current min and max
+// instances, stopping at zero. See ScaleUp.
+//
+// ScaleUp, ScaleDown, ScaleMinBy and ScaleMaxBy all respect WithMinFloor and
+// WithMaxCeiling.
+func ScaleDown(ctx context.Context, deltaMin, deltaMax int, opts ...ScaleOption) error {
+	_, err := Scale(ctx, withOptions(opts, withAdjust(-deltaMin, -deltaMax))...)
+	return err
+}
+
+// ScaleMaxBy adds delta, which may be negative, to the service's current max instances
+// and returns the new value. Use WithMaxCeiling to cap the result. See ScaleUp.
+func ScaleMaxBy(ctx context.Context, delta int, opts ...ScaleOption) (newMax int, err error) {
+	result, err := Scale(ctx, withOptions(opts, withAdjust(0, delta))...)
+	if err != nil {
+		return 0, err
+	}
+	return result.NewMax, nil
+}
+
+// ScaleMinBy adds delta, which may be negative, to the service's current min instances
+// and returns the new value. Use WithMinFloor to bound the result. The max instances
+// are left as they are, including unset. See ScaleUp.
+func ScaleMinBy(ctx context.Context, delta int, opts ...ScaleOption) (newMin int, err error) {
+	result, err := Scale(ctx, withOptions(opts, withAdjust(delta, 0))...)
+	if err != nil {
+		return 0, err
+	}
+	return result.NewMin, nil
+}
+
+// applyAdjust checks the service's current values against c.check, then replaces c.Min
+// and c.Max with values computed from them, for the relative scaling functions. It
+// runs on every read of the service, so both see the values being updated.
+func (c *Config) applyAdjust(oldMin, oldMax int) error {
+	if c.check != nil {
+		if err := c.check(oldMin, oldMax); err != nil {
+			return &refusedError{err: err}
+		}
+	}
+	if c.adjust == nil {
+		return nil
+	}
+	if err := c.adjust(c, oldMin, oldMax); err != nil {
+		return err
+	}
+	if c.MaxCeiling > 0 && c.Max > c.MaxCeiling {
+		c.Max = c.MaxCeiling
+	}
+	if c.Min < c.MinFloor {
+		c.Min = c.MinFloor
+	}
+	return c.validateScaling()
+}
+
+// withAdjust adds deltaMin and deltaMax to the service's current values. A service
+// without max instances, which Cloud Run leaves at its default, keeps none unless
+// deltaMax asks to change it, which is refused as there is no value to add it to.
+func withAdjust(deltaMin, deltaMax int) ScaleOption {
+	return func(c *Config) {
+		c.adjust = func(c *Config, oldMin, oldMax int) error {
+			c.Min = clampZero(oldMin + deltaMin)
+			c.keepMax = oldMax == 0
+			if !c.keepMax {
+				c.Max = clampZero(oldMax + deltaMax)
+				return nil
+			}
+			c.Max = 0
+			if deltaMax != 0 {
+				return &ValidationError{Min: c.Min, Max: 0, Reason: "the service has no max instances to adjust; set one with Scale first"}
+			}
+			return nil
+		}
+	}
+}
+
+func clampZero(n int) int {
+	if n < 0 {
+		return 0
+	}
+	return n
+}
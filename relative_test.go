@@ -0,0 +1,59 @@
+package scale_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	scale "github.com/darrenmcc/run-scaler"
+	"github.com/darrenmcc/run-scaler/scaletest"
+)
+
+func TestScaleUpWithoutMax(t *testing.T) {
+	srv, _ := scaletest.NewTestServer(t, newService(map[string]string{
+		"autoscaling.knative.dev/minScale": "1",
+	}))
+
+	if err := scale.ScaleUp(context.Background(), 2, 0, serverOptions(srv)...); err != nil {
+		t.Fatalf("ScaleUp: %v", err)
+	}
+	annotations := scaletest.Service(srv).Spec.Template.Metadata.Annotations
+	if got := annotations["autoscaling.knative.dev/minScale"]; got != "3" {
+		t.Errorf("minScale = %q, want 3", got)
+	}
+	if got, ok := annotations["autoscaling.knative.dev/maxScale"]; ok {
+		t.Errorf("maxScale = %q, want it left unset", got)
+	}
+}
+
+func TestScaleUpMaxWithoutMax(t *testing.T) {
+	srv, _ := scaletest.NewTestServer(t, newService(nil))
+
+	err := scale.ScaleUp(context.Background(), 0, 5, serverOptions(srv)...)
+	var validationErr *scale.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("ScaleUp error = %v, want a *ValidationError", err)
+	}
+	for _, r := range scaletest.Requests(srv) {
+		if r.Method == http.MethodPut {
+			t.Errorf("service updated: %s", r.Body)
+		}
+	}
+}
+
+func TestScaleMinByWithoutMax(t *testing.T) {
+	srv, _ := scaletest.NewTestServer(t, newService(nil))
+
+	newMin, err := scale.ScaleMinBy(context.Background(), 4, serverOptions(srv)...)
+	if err != nil {
+		t.Fatalf("ScaleMinBy: %v", err)
+	}
+	if newMin != 4 {
+		t.Errorf("new min = %d, want 4", newMin)
+	}
+	annotations := scaletest.Service(srv).Spec.Template.Metadata.Annotations
+	if got, ok := annotations["autoscaling.knative.dev/maxScale"]; ok {
+		t.Errorf("maxScale = %q, want it left unset", got)
+	}
+}
@@ -0,0 +1,103 @@
+package scale
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+)
+
+// retryPolicy controls how calls to the Cloud Run API are retried.
+type retryPolicy struct {
+	maxAttempts int
+	baseDelay   time.Duration
+}
+
+// do sends a request to the Cloud Run API, retrying transient failures according to p.
+// The response of the last attempt is returned and must be closed by the caller.
+func (p retryPolicy) do(ctx context.Context, c *http.Client, method, url string, body []byte) (*http.Response, error) {
+	attempts := p.maxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	for attempt := 1; ; attempt++ {
+		var r io.Reader
+		if body != nil {
+			r = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, r)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.Do(req)
+		if attempt >= attempts || !retryable(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		// full jitter: sleep a random duration between 0 and baseDelay * 2^(attempt-1)
+		backoff := p.baseDelay << (attempt - 1)
+		var delay time.Duration
+		if backoff > 0 {
+			delay = time.Duration(rand.Int63n(int64(backoff)))
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			if err == nil {
+				err = ctx.Err()
+			}
+			return nil, err
+		case <-t.C:
+		}
+	}
+}
+
+// retryable reports whether a call to the Cloud Run API failed in a way that is
+// worth trying again.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return true
+		}
+		var opErr *net.OpError
+		return errors.As(err, &opErr)
+	}
+	return retryableStatus(resp.StatusCode)
+}
+
+// retryableStatus reports whether an API response code is worth trying again.
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// transient reports whether a failed Scale is worth trying again.
+func transient(err error) bool {
+	if errors.Is(err, ErrConcurrentModification) || errors.Is(err, ErrScaleInProgress) {
+		return true
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return retryableStatus(apiErr.StatusCode)
+	}
+	return retryable(nil, err)
+}
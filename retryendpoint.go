@@ -0,0 +1,47 @@
+//go:build !nogokit
+
+package scale
+
+import (
+	"context"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/go-kit/kit/endpoint"
+)
+
+// NewRetryEndpoint is like NewEndpoint with WithMin(min) and WithMax(max), but retries
+// a failed Scale up to retries times, waiting between attempts as b says, e.g.
+// backoff.NewExponentialBackOff(). Retrying inside the endpoint keeps it invisible to
+// the transport, which would otherwise decode the request again. Only transient
+// failures are retried: 429 and 5xx API responses, network errors, conflicting
+// updates and a held lock. Waits end early when the request's context is done.
+// A *backoff.ExponentialBackOff is copied for every request; any other b is shared by
+// concurrent requests and must be safe for that.
+func NewRetryEndpoint(min, max int, retries int, b backoff.BackOff, opts ...ScaleOption) endpoint.Endpoint {
+	opts = withOptions(opts, WithMin(min), WithMax(max))
+	if retries < 0 {
+		retries = 0
+	}
+	return func(ctx context.Context, _ interface{}) (interface{}, error) {
+		bo := b
+		if eb, ok := b.(*backoff.ExponentialBackOff); ok {
+			c := *eb
+			bo = &c
+		}
+		bo.Reset()
+
+		var result *ScaleResult
+		err := backoff.Retry(func() error {
+			var err error
+			result, err = Scale(ctx, opts...)
+			if err != nil && !transient(err) {
+				return backoff.Permanent(err)
+			}
+			return err
+		}, backoff.WithContext(backoff.WithMaxRetries(bo, uint64(retries)), ctx))
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+}
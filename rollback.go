@@ -0,0 +1,115 @@
+package scale
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"google.golang.org/api/run/v1"
+)
+
+// ErrNoPreviousRevision is returned by Rollback when the service has only one revision.
+var ErrNoPreviousRevision = errors.New("service has no previous revision to roll back to")
+
+// Rollback restores the min and max instances of the service's previous revision,
+// undoing the most recent Scale. Use WithRevision to restore the values of a specific
+// revision instead. Rolling back creates a new revision like any other Scale. A
+// revision without max instances is restored by removing the service's.
+func Rollback(ctx context.Context, opts ...ScaleOption) error {
+	c, err := NewClient(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	c, cfg := c.config(ctx)
+
+	var rev *run.Revision
+	if cfg.Revision != "" {
+		rev, err = c.getRevision(ctx, &cfg, cfg.Revision)
+	} else {
+		rev, err = c.previousRevision(ctx, &cfg)
+	}
+	if err != nil {
+		return err
+	}
+
+	min, max, err := annotationScaling(rev.Metadata.Annotations)
+	if err != nil {
+		return fmt.Errorf("revision %s: %w", rev.Metadata.Name, err)
+	}
+	extra := []ScaleOption{WithMin(min), WithMax(max)}
+	if max == 0 {
+		// the revision had no max instances, so neither should the service
+		extra = append(extra, withoutMax())
+	}
+	c, cfg = c.config(ctx, extra...)
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	_, err = c.scale(ctx, cfg)
+	return err
+}
+
+// previousRevision returns the second most recently created revision of the service.
+func (c *Client) previousRevision(ctx context.Context, cfg *Config) (*run.Revision, error) {
+	revs, err := c.listRevisions(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(revs) < 2 {
+		return nil, ErrNoPreviousRevision
+	}
+	// RFC 3339 timestamps in UTC sort lexically
+	sort.Slice(revs, func(i, j int) bool {
+		return revs[i].Metadata.CreationTimestamp > revs[j].Metadata.CreationTimestamp
+	})
+	return revs[1], nil
+}
+
+func (c *Client) revisionsURL(cfg *Config) string {
+	return c.servingURL(cfg) + "/revisions"
+}
+
+// listRevisions returns every revision of the service.
+func (c *Client) listRevisions(ctx context.Context, cfg *Config) ([]*run.Revision, error) {
+	u := c.revisionsURL(cfg) + "?labelSelector=" + url.QueryEscape("serving.knative.dev/service="+cfg.Service)
+	resp, err := cfg.call(ctx, c.httpClient, "run-scaler/list-revisions", c.project, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("list revisions of %s: %w", cfg.Service, newAPIError(resp))
+	}
+
+	var list run.ListRevisionsResponse
+	err = json.NewDecoder(resp.Body).Decode(&list)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// getRevision fetches a single revision by name.
+func (c *Client) getRevision(ctx context.Context, cfg *Config, name string) (*run.Revision, error) {
+	resp, err := cfg.call(ctx, c.httpClient, "run-scaler/get-revision", c.project, http.MethodGet, c.revisionsURL(cfg)+"/"+name, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get revision %s: %w", name, newAPIError(resp))
+	}
+
+	var rev run.Revision
+	err = json.NewDecoder(resp.Body).Decode(&rev)
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
@@ -0,0 +1,29 @@
+package scale_test
+
+import (
+	"context"
+	"testing"
+
+	scale "github.com/darrenmcc/run-scaler"
+	"github.com/darrenmcc/run-scaler/scaletest"
+)
+
+func TestRollbackUnsetMax(t *testing.T) {
+	svc := newService(map[string]string{"autoscaling.knative.dev/minScale": "2"})
+	svc.Spec.Template.Metadata.Name = "my-svc-00001-abc"
+	srv, c := scaletest.NewTestServer(t, svc)
+	if err := c.Scale(context.Background(), 5, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := scale.Rollback(context.Background(), serverOptions(srv)...); err != nil {
+		t.Fatal(err)
+	}
+	annotations := scaletest.Service(srv).Spec.Template.Metadata.Annotations
+	if got := annotations["autoscaling.knative.dev/minScale"]; got != "2" {
+		t.Errorf("minScale = %q, want 2", got)
+	}
+	if got, ok := annotations["autoscaling.knative.dev/maxScale"]; ok {
+		t.Errorf("maxScale = %q, want it removed", got)
+	}
+}
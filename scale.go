@@ -1,117 +1,335 @@
 package scale
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"os"
+	"path"
 	"strconv"
+	"time"
 
-	"cloud.google.com/go/compute/metadata"
-	"github.com/go-kit/kit/endpoint"
-	"golang.org/x/oauth2/google"
 	"google.golang.org/api/run/v1"
 )
 
+const (
+	minScaleAnnotation = "autoscaling.knative.dev/minScale"
+	maxScaleAnnotation = "autoscaling.knative.dev/maxScale"
+	targetAnnotation   = "autoscaling.knative.dev/target"
+
+	startupCPUBoostAnnotation      = "run.googleapis.com/startup-cpu-boost"
+	executionEnvironmentAnnotation = "run.googleapis.com/execution-environment"
+	ingressAnnotation              = "run.googleapis.com/ingress"
+	vpcConnectorAnnotation         = "run.googleapis.com/vpc-access-connector"
+	vpcEgressAnnotation            = "run.googleapis.com/vpc-access-egress"
+	launchStageAnnotation          = "run.googleapis.com/launch-stage"
+	sessionAffinityAnnotation      = "run.googleapis.com/sessionAffinity"
+	cpuThrottlingAnnotation        = "run.googleapis.com/cpu-throttling"
+)
+
 // Scale allows a Cloud Run service to modify itself with the given scaling parameters on the fly.
-// Min and max correspond to min and max instances. Calling this creates a new revision.
+// WithMin and WithMax correspond to min and max instances. Calling this creates a new revision.
 // Designed to work on a cron-like schedule to preempt large traffic changes that can't
 // be gracefully handled by Cloud Run's normal autoscaling capabilities.
 //
 // Example use cases:
 // - scale service to handle large data pushes from an outside provider that occur on a regular schedule
 // - allow for more idle instances during unpredictable daytime traffic and then scale back down at night
-func Scale(ctx context.Context, min, max int) error {
-	httpClient, err := google.DefaultClient(ctx, run.CloudPlatformScope)
+//
+// The project and service can also be set per call with ProjectFromContext and
+// ServiceFromContext, which take precedence over options and the environment.
+func Scale(ctx context.Context, opts ...ScaleOption) (*ScaleResult, error) {
+	opts = withOptions(opts, contextOptions(ctx)...)
+	cfg := newConfig(opts)
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	c, err := newClient(ctx, &cfg, opts)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return c.scale(ctx, cfg)
+}
 
-	project, err := metadata.ProjectID()
-	if err != nil {
-		return err
+// scale applies cfg to the service. cfg must already be validated.
+func (c *Client) scale(ctx context.Context, cfg Config) (result *ScaleResult, err error) {
+	defer func() { cfg.metrics.observeScale(&cfg, result, err) }()
+	if err := cfg.rateLimiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	if cfg.capBypass != nil {
+		cfg.log(ctx, "scale: bypassing the global max cap", "service", cfg.Service, "reason", cfg.capBypass.reason)
+	}
+	if err := cfg.breaker.allow(); err != nil {
+		return nil, err
+	}
+	defer func() {
+		switch {
+		case inconclusive(err):
+			cfg.breaker.abandon()
+		case countsAsFailure(err):
+			cfg.breaker.record(err)
+		default:
+			cfg.breaker.record(nil)
+		}
+	}()
+	if lock := c.lock(&cfg); lock != nil && !cfg.DryRun {
+		if err := lock.Acquire(ctx, c.lockKey(&cfg)); err != nil {
+			return nil, err
+		}
+		defer c.releaseLock(ctx, &cfg, lock)
+	}
+	if cfg.APIVersion == APIv2 {
+		return c.scaleV2(ctx, cfg)
 	}
 
-	runAdminURL := fmt.Sprintf(
-		"https://us-central1-run.googleapis.com/apis/serving.knative.dev/v1/namespaces/%s/services/%s",
-		project, os.Getenv("K_SERVICE"))
+	// on a conflict start over from a fresh read, like any Kubernetes controller
+	for attempt := 1; ; attempt++ {
+		result, err = c.scaleV1(ctx, &cfg)
+		if !errors.Is(err, ErrConcurrentModification) || attempt > cfg.conflictRetries {
+			return result, err
+		}
+		cfg.log(ctx, "scale: service modified concurrently, retrying", "service", cfg.Service, "attempt", attempt)
+	}
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, runAdminURL, nil)
-	if err != nil {
-		return err
+// lockReleaseTimeout bounds releasing the lock once a scale is done.
+const lockReleaseTimeout = 10 * time.Second
+
+// releaseLock releases the lock held for cfg.Service. It runs even if ctx is done,
+// e.g. the handler's client disconnected, so that the lock is not left to expire.
+func (c *Client) releaseLock(ctx context.Context, cfg *Config, lock LockProvider) {
+	rctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), lockReleaseTimeout)
+	defer cancel()
+	if err := lock.Release(rctx, c.lockKey(cfg)); err != nil {
+		cfg.log(ctx, "scale: releasing lock failed", "service", cfg.Service, "error", err)
 	}
-	svcResp, err := httpClient.Do(req)
+}
+
+// lockKey identifies cfg.Service to a LockProvider, across projects and regions.
+func (c *Client) lockKey(cfg *Config) string {
+	return path.Join(c.project, cfg.Region, cfg.Service)
+}
+
+// scaleV1 reads, modifies and updates the service once through the v1 API.
+func (c *Client) scaleV1(ctx context.Context, cfg *Config) (*ScaleResult, error) {
+	svc, err := c.get(ctx, cfg)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer svcResp.Body.Close()
 
-	var svc run.Service
-	err = json.NewDecoder(svcResp.Body).Decode(&svc)
-	if err != nil {
-		return err
+	// malformed existing annotations are reported as 0 and simply overwritten
+	oldMin, oldMax, _ := currentScaling(svc)
+	if err := cfg.applyAdjust(oldMin, oldMax); err != nil {
+		return nil, err
+	}
+	result := &ScaleResult{
+		OldMin: oldMin,
+		OldMax: oldMax,
+		NewMin: cfg.Min,
+		NewMax: cfg.revisionMax(),
+	}
+	logAttrs := []any{
+		"project", c.project,
+		"service", cfg.Service,
+		"region", cfg.Region,
+		"old_min", oldMin,
+		"old_max", oldMax,
+		"new_min", cfg.Min,
+		"new_max", cfg.revisionMax(),
 	}
 
 	// noop if new scaling values are same as current
-	newMin := strconv.Itoa(min)
-	newMax := strconv.Itoa(max)
-	if svc.Spec.Template.Metadata.Annotations["autoscaling.knative.dev/minScale"] == newMin &&
-		svc.Spec.Template.Metadata.Annotations["autoscaling.knative.dev/maxScale"] == newMax {
-		return nil
+	if !applyTemplate(svc, cfg) {
+		cfg.log(ctx, "scale noop: service already at requested values", logAttrs...)
+		result.Noop = true
+		c.scaled(ctx, cfg, result)
+		return result, nil
 	}
 
-	// BETA annotation required on top-level metadata for minScale setting
-	svc.Metadata.Annotations["run.googleapis.com/launch-stage"] = "BETA"
+	if cfg.AnnotationMerge {
+		// start over from the latest version of the service, so that anything
+		// changed since the first read is kept, and set only our keys on it
+		svc, err = c.get(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		applyTemplate(svc, cfg)
+	}
+
+	setLaunchStage(svc, cfg.Min)
 	// zero out name so new revision name is generated, or else request will
-	// fail because service with this name already exists
-	svc.Spec.Template.Metadata.Name = ""
-	svc.Spec.Template.Metadata.Annotations["autoscaling.knative.dev/minScale"] = newMin
-	svc.Spec.Template.Metadata.Annotations["autoscaling.knative.dev/maxScale"] = newMax
+	// fail because service with this name already exists; with a suffix the
+	// caller is responsible for the name being new
+	svc.Spec.Template.Metadata.Name = cfg.revisionName()
+	if cfg.TrafficPercent != nil {
+		splitTraffic(svc, *cfg.TrafficPercent)
+	}
 
 	b, err := json.Marshal(svc)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if cfg.DryRun {
+		return nil, &DryRunResult{svc: svc, json: b}
 	}
-	req, err = http.NewRequestWithContext(ctx, http.MethodPut, runAdminURL, bytes.NewBuffer(b))
+
+	cfg.log(ctx, "scale: updating service", logAttrs...)
+	updated, err := c.update(ctx, cfg, b)
 	if err != nil {
-		return err
+		cfg.log(ctx, "scale: update failed", append(logAttrs, "error", err)...)
+		return nil, err
 	}
-	updateResp, err := httpClient.Do(req)
+	result.RevisionName = createdRevision(updated)
+	result.UpdatedAt = time.Now()
+	cfg.log(ctx, "scale: service updated", append(logAttrs, "revision", result.RevisionName)...)
+	if cfg.tag != "" && result.RevisionName != "" {
+		if tagged, err := c.tagRevision(ctx, cfg, updated, result.RevisionName); err != nil {
+			cfg.log(ctx, "scale: tagging revision failed", append(logAttrs, "revision", result.RevisionName, "tag", cfg.tag, "error", err)...)
+		} else {
+			updated = tagged
+		}
+	}
+	if cfg.maxRevisionAge > 0 {
+		c.pruneRevisions(ctx, cfg, updated)
+	}
+	c.scaled(ctx, cfg, result)
+	return result, nil
+}
+
+// setLaunchStage sets the BETA launch stage on svc when min instances need it, that is
+// when min is above zero and the service is not already on a pre-GA stage such as
+// ALPHA. Otherwise the launch stage is left as it is.
+func setLaunchStage(svc *run.Service, min int) {
+	if min == 0 {
+		return
+	}
+	switch svc.Metadata.Annotations[launchStageAnnotation] {
+	case "", "GA":
+		svc.Metadata.Annotations[launchStageAnnotation] = "BETA"
+	}
+}
+
+// ScaleResult describes the outcome of a call to Scale.
+type ScaleResult struct {
+	// Noop is true if the service was already at the requested values
+	// and no new revision was created.
+	Noop bool
+	// RevisionName is the name of the revision created by the update, as reported
+	// in the Cloud Run API response. It may be empty if Cloud Run had not yet
+	// assigned a name when it responded.
+	RevisionName string
+
+	OldMin, OldMax int
+	NewMin, NewMax int
+	// UpdatedAt is when the update was acknowledged. It is zero for noops.
+	UpdatedAt time.Time
+}
+
+// revisionName returns the name to give the new revision, or "" to have Cloud Run
+// generate one.
+func (c *Config) revisionName() string {
+	if c.RevisionSuffix == "" {
+		return ""
+	}
+	return c.Service + "-" + c.RevisionSuffix
+}
+
+// createdRevision returns the name of the revision described by an update response.
+func createdRevision(svc *run.Service) string {
+	if svc.Spec != nil && svc.Spec.Template != nil && svc.Spec.Template.Metadata != nil &&
+		svc.Spec.Template.Metadata.Name != "" {
+		return svc.Spec.Template.Metadata.Name
+	}
+	if svc.Status != nil {
+		return svc.Status.LatestCreatedRevisionName
+	}
+	return ""
+}
+
+// GetScaling returns the current min and max instances of the service without
+// modifying anything. Annotations that are not set on the service are returned as 0.
+func GetScaling(ctx context.Context, opts ...ScaleOption) (min, max int, err error) {
+	c, err := NewClient(ctx, opts...)
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
-	defer updateResp.Body.Close()
+	return c.GetScaling(ctx)
+}
+
+// currentScaling parses the min and max instance annotations of svc's revision template.
+func currentScaling(svc *run.Service) (min, max int, err error) {
+	return annotationScaling(svc.Spec.Template.Metadata.Annotations)
+}
 
-	if updateResp.StatusCode != http.StatusOK {
-		return fmt.Errorf("cloud Run API response code: %d", updateResp.StatusCode)
+// annotationScaling parses the min and max instance annotations, returning 0 for any
+// that are not set.
+func annotationScaling(annotations map[string]string) (min, max int, err error) {
+	if min, err = annotationInt(annotations, minScaleAnnotation); err != nil {
+		return 0, 0, err
 	}
-	return nil
+	if max, err = annotationInt(annotations, maxScaleAnnotation); err != nil {
+		return 0, 0, err
+	}
+	return min, max, nil
 }
 
-// NewHandler can be used in any http service e.g.
-// router.HandleFunc("/scale/up", scale.NewHandler(100, 1000))
-// router.HandleFunc("/scale/down", scale.NewHandler(0, 1000))
-func NewHandler(min, max int) func(http.ResponseWriter, *http.Request) {
-	return func(w http.ResponseWriter, _ *http.Request) {
-		err := Scale(context.Background(), min, max)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
-		w.WriteHeader(http.StatusOK)
+func annotationInt(annotations map[string]string, key string) (int, error) {
+	v, ok := annotations[key]
+	if !ok || v == "" {
+		return 0, nil
 	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s annotation %q: %w", key, v, err)
+	}
+	return n, nil
 }
 
-// NewEndpoint can be used as a go-kit endpoint in any Gizmo service e.g.
-// "/scale/up": {
-//     "POST": {
-//         Endpoint: scale.NewEndpoint(100, 1000),
-//     },
-// },
-func NewEndpoint(min, max int) endpoint.Endpoint {
-	return func(ctx context.Context, _ interface{}) (interface{}, error) {
-		return nil, Scale(ctx, min, max)
+// ScaleMinMax is a shorthand for Scale with WithMin and WithMax, kept for callers of
+// the original Scale(ctx, min, max) signature.
+func ScaleMinMax(ctx context.Context, min, max int, opts ...ScaleOption) error {
+	_, err := Scale(ctx, withOptions(opts, WithMin(min), WithMax(max))...)
+	return err
+}
+
+// ScaleWithTimeout is like ScaleMinMax but gives up after timeout, e.g. to leave a
+// handler time to respond before its own deadline.
+func ScaleWithTimeout(ctx context.Context, timeout time.Duration, min, max int, opts ...ScaleOption) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return ScaleMinMax(ctx, min, max, opts...)
+}
+
+// ScaleIf scales the service to min and max if condition, e.g. a check of a load
+// metric, reports true. If it reports false nothing is done and nil is returned; if
+// it fails, its error is returned without scaling.
+func ScaleIf(ctx context.Context, condition func(ctx context.Context) (bool, error), min, max int, opts ...ScaleOption) error {
+	ok, err := condition(ctx)
+	if err != nil || !ok {
+		return err
 	}
+	return ScaleMinMax(ctx, min, max, opts...)
+}
+
+// ScaleWithConcurrency behaves like ScaleMinMax but also sets the target number of concurrent
+// requests per instance, both as the revision's containerConcurrency and as the
+// autoscaling.knative.dev/target annotation.
+func ScaleWithConcurrency(ctx context.Context, min, max, concurrency int, opts ...ScaleOption) error {
+	_, err := Scale(ctx, withOptions(opts, WithMin(min), WithMax(max), WithContainerConcurrency(concurrency))...)
+	return err
+}
+
+// NewHandler can be used in any http service e.g.
+// router.HandleFunc("/scale/up", scale.NewHandler(scale.WithMin(100), scale.WithMax(1000)))
+// router.HandleFunc("/scale/down", scale.NewHandler(scale.WithMin(0), scale.WithMax(1000)))
+// By default only the status code is written; see WithJSONResponse for a response body.
+// Middleware set with WithMiddleware wraps the handler.
+func NewHandler(opts ...ScaleOption) func(http.ResponseWriter, *http.Request) {
+	cfg := newConfig(opts)
+	return cfg.wrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, err := Scale(r.Context(), opts...)
+		writeScaleResult(w, result, err, cfg.JSONResponse)
+	})).ServeHTTP
 }
@@ -0,0 +1,132 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// defaultParallelism is how many services ScaleAll scales at once unless
+// WithConcurrency is given.
+const defaultParallelism = 10
+
+// ServiceTarget is a service and the min and max instances ScaleAll should scale it to.
+type ServiceTarget struct {
+	Service  string
+	Min, Max int
+}
+
+// ServiceScaleError records the failure to scale a single service in ScaleAll.
+type ServiceScaleError struct {
+	// Project is set when scaling across the projects given to WithProjectList.
+	Project string
+	Service string
+	Err     error
+}
+
+func (e ServiceScaleError) Error() string {
+	if e.Project != "" {
+		return fmt.Sprintf("scaling %s in %s: %s", e.Service, e.Project, e.Err)
+	}
+	return fmt.Sprintf("scaling %s: %s", e.Service, e.Err)
+}
+
+func (e ServiceScaleError) Unwrap() error {
+	return e.Err
+}
+
+// ScaleAll scales every target concurrently, running at most WithConcurrency calls
+// at once. A failure on one service does not stop the others; the returned slice
+// holds an entry for every service that could not be scaled and is empty if all
+// succeeded. The given options apply to every target, except WithService, WithMin
+// and WithMax which are taken from each ServiceTarget.
+//
+// With WithProjectList every target is scaled in each of the listed projects, each
+// with its own client (see WithProjectImpersonation).
+func ScaleAll(ctx context.Context, targets []ServiceTarget, opts ...ScaleOption) []ServiceScaleError {
+	cfg := newConfig(opts)
+	projects := cfg.ProjectList
+	if len(projects) == 0 {
+		// a single client for the project given by opts or the metadata server
+		projects = []string{""}
+	}
+
+	parallelism := cfg.Parallelism
+	if parallelism <= 0 {
+		parallelism = defaultParallelism
+	}
+
+	var (
+		mu   sync.Mutex
+		errs []ServiceScaleError
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, parallelism)
+	)
+	fail := func(project, service string, err error) {
+		mu.Lock()
+		errs = append(errs, ServiceScaleError{Project: project, Service: service, Err: err})
+		mu.Unlock()
+	}
+	for _, project := range projects {
+		c, err := NewClient(ctx, withOptions(opts, cfg.projectOptions(project)...)...)
+		if err != nil {
+			for _, t := range targets {
+				fail(project, t.Service, err)
+			}
+			continue
+		}
+		for _, t := range targets {
+			wg.Add(1)
+			go func(t ServiceTarget) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				extra := []ScaleOption{WithService(t.Service), WithMin(t.Min), WithMax(t.Max)}
+				if project != "" {
+					// the listed project, even if ctx sets another
+					extra = append(extra, WithProject(project))
+				}
+				c, cfg := c.config(ctx, extra...)
+				err := cfg.validate()
+				if err == nil {
+					_, err = c.scale(ctx, cfg)
+				}
+				if err != nil {
+					fail(project, t.Service, err)
+				}
+			}(t)
+		}
+	}
+	wg.Wait()
+	return errs
+}
+
+// WithProjectList makes ScaleAll scale its targets in each of projects, e.g. the
+// spoke projects managed from a central hub project.
+func WithProjectList(projects []string) ScaleOption {
+	return func(c *Config) {
+		c.ProjectList = projects
+	}
+}
+
+// WithProjectImpersonation sets the service account to impersonate, by project, when
+// ScaleAll scales across the projects given to WithProjectList. Projects not in
+// serviceAccounts use the default credentials, or WithImpersonateServiceAccount.
+func WithProjectImpersonation(serviceAccounts map[string]string) ScaleOption {
+	return func(c *Config) {
+		c.ProjectImpersonation = serviceAccounts
+	}
+}
+
+// projectOptions returns the options selecting project, or none for "".
+func (c *Config) projectOptions(project string) []ScaleOption {
+	if project == "" {
+		return nil
+	}
+	opts := []ScaleOption{WithProject(project)}
+	if sa, ok := c.ProjectImpersonation[project]; ok {
+		opts = append(opts, WithImpersonateServiceAccount(sa))
+	}
+	return opts
+}
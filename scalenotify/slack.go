@@ -0,0 +1,64 @@
+// Package scalenotify provides notifiers for scale.WithNotifier.
+package scalenotify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	scale "github.com/darrenmcc/run-scaler"
+)
+
+// NewSlackNotifier returns a notifier posting a message about every event to a Slack
+// incoming webhook e.g.
+//
+//	scale.Scale(ctx, scale.WithMin(5), scale.WithMax(100),
+//	    scale.WithNotifier(scalenotify.NewSlackNotifier(webhookURL)))
+func NewSlackNotifier(webhookURL string) func(ctx context.Context, event scale.ScaleEvent) error {
+	return func(ctx context.Context, event scale.ScaleEvent) error {
+		b, err := json.Marshal(struct {
+			Text string `json:"text"`
+		}{Text: Message(event)})
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+			return fmt.Errorf("slack webhook responded %d: %s", resp.StatusCode, body)
+		}
+		return nil
+	}
+}
+
+// Message describes event in one line of text, for notifiers to send.
+func Message(event scale.ScaleEvent) string {
+	target := fmt.Sprintf("%s (%s/%s)", event.Service, event.Project, event.Region)
+	var msg string
+	if event.Noop {
+		msg = fmt.Sprintf("%s already at min=%d max=%d", target, event.NewMin, event.NewMax)
+	} else {
+		msg = fmt.Sprintf("%s scaled from min=%d max=%d to min=%d max=%d", target,
+			event.OldMin, event.OldMax, event.NewMin, event.NewMax)
+		if event.RevisionName != "" {
+			msg += ", revision " + event.RevisionName
+		}
+	}
+	if event.Initiator != "" {
+		msg += " by " + event.Initiator
+	}
+	return msg
+}
@@ -0,0 +1,5 @@
+// Package scalepb holds the protobuf and gRPC definitions of ScaleService, served by
+// scale.NewGRPCServer.
+package scalepb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative scale.proto
@@ -0,0 +1,248 @@
+// Package scaletest provides an in-process stand-in for the Cloud Run Admin API, for
+// testing code that scales services without calling Google Cloud.
+package scaletest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	scale "github.com/darrenmcc/run-scaler"
+	"google.golang.org/api/run/v1"
+)
+
+const defaultNamespace = "default"
+
+// Request is a call received by a test server.
+type Request struct {
+	Method string
+	Path   string
+	// Body is the body of a PUT, or nil.
+	Body []byte
+}
+
+// server holds the state of a test server.
+type server struct {
+	mu            sync.Mutex
+	path          string
+	revisionsPath string
+	svc           *run.Service
+	revisions     []*run.Revision
+	requests      []Request
+}
+
+var (
+	serversMu sync.Mutex
+	servers   = map[*httptest.Server]*server{}
+)
+
+// NewTestServer starts a server that serves initialService, which must have a name,
+// from the serving.knative.dev/v1 API, and a Client that scales it there. GETs return
+// the current service and PUTs replace it, much like Cloud Run: a PUT is refused
+// with 409 Conflict unless it carries the current resourceVersion, and every update
+// bumps the generation and gets a revision name if it has none. Each update also
+// creates a revision, ready at once, which can be read and listed like on Cloud Run;
+// so does the initial service if its template has a name. opts are passed on to the
+// Client. The server is closed when the test ends.
+func NewTestServer(t *testing.T, initialService *run.Service, opts ...scale.ScaleOption) (*httptest.Server, *scale.Client) {
+	t.Helper()
+	if initialService.Metadata == nil || initialService.Metadata.Name == "" {
+		t.Fatal("scaletest: initial service has no name")
+	}
+	svc := clone(t, initialService)
+	if svc.Metadata.Namespace == "" {
+		svc.Metadata.Namespace = defaultNamespace
+	}
+	if svc.Metadata.ResourceVersion == "" {
+		svc.Metadata.ResourceVersion = "1"
+	}
+	namespace := fmt.Sprintf("/apis/serving.knative.dev/v1/namespaces/%s", svc.Metadata.Namespace)
+	s := &server{
+		path:          namespace + "/services/" + svc.Metadata.Name,
+		revisionsPath: namespace + "/revisions",
+		svc:           svc,
+	}
+	if svc.Spec != nil && svc.Spec.Template != nil && svc.Spec.Template.Metadata != nil &&
+		svc.Spec.Template.Metadata.Name != "" {
+		s.addRevision()
+	}
+
+	srv := httptest.NewServer(s)
+	serversMu.Lock()
+	servers[srv] = s
+	serversMu.Unlock()
+	t.Cleanup(func() {
+		srv.Close()
+		serversMu.Lock()
+		delete(servers, srv)
+		serversMu.Unlock()
+	})
+
+	client, err := scale.NewClient(context.Background(), append([]scale.ScaleOption{
+		scale.WithKnativeEndpoint(srv.URL),
+		scale.WithHTTPClient(srv.Client()),
+		scale.WithProject(svc.Metadata.Namespace),
+		scale.WithService(svc.Metadata.Name),
+	}, opts...)...)
+	if err != nil {
+		t.Fatalf("scaletest: creating client: %v", err)
+	}
+	return srv, client
+}
+
+// Requests returns the calls srv, a server started by NewTestServer, has received so far.
+func Requests(srv *httptest.Server) []Request {
+	s := lookup(srv)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Service returns the service as currently stored by srv, a server started by
+// NewTestServer.
+func Service(srv *httptest.Server) *run.Service {
+	s := lookup(srv)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var svc run.Service
+	b, _ := json.Marshal(s.svc)
+	json.Unmarshal(b, &svc)
+	return &svc
+}
+
+func lookup(srv *httptest.Server) *server {
+	serversMu.Lock()
+	defer serversMu.Unlock()
+	s, ok := servers[srv]
+	if !ok {
+		panic("scaletest: server not started by NewTestServer")
+	}
+	return s
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	req := Request{Method: r.Method, Path: r.URL.Path}
+	if r.Method == http.MethodPut {
+		req.Body = body
+	}
+	s.requests = append(s.requests, req)
+
+	if r.Method == http.MethodGet && (r.URL.Path == s.revisionsPath || strings.HasPrefix(r.URL.Path, s.revisionsPath+"/")) {
+		s.serveRevisions(w, r)
+		return
+	}
+	if r.URL.Path != s.path {
+		http.NotFound(w, r)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var svc run.Service
+		if err := json.Unmarshal(body, &svc); err != nil || svc.Metadata == nil {
+			http.Error(w, "invalid service", http.StatusBadRequest)
+			return
+		}
+		if svc.Metadata.ResourceVersion != s.svc.Metadata.ResourceVersion {
+			http.Error(w, "the service has been modified; please apply your changes to the latest version", http.StatusConflict)
+			return
+		}
+		s.store(&svc)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.svc)
+}
+
+// store replaces the service with svc as an update would.
+func (s *server) store(svc *run.Service) {
+	version, _ := strconv.Atoi(s.svc.Metadata.ResourceVersion)
+	svc.Metadata.ResourceVersion = strconv.Itoa(version + 1)
+	svc.Metadata.Generation = s.svc.Metadata.Generation + 1
+	if svc.Spec != nil && svc.Spec.Template != nil {
+		if svc.Spec.Template.Metadata == nil {
+			svc.Spec.Template.Metadata = &run.ObjectMeta{}
+		}
+		if svc.Spec.Template.Metadata.Name == "" {
+			svc.Spec.Template.Metadata.Name = fmt.Sprintf("%s-%05d-tst", svc.Metadata.Name, svc.Metadata.Generation)
+		}
+		if svc.Status == nil {
+			svc.Status = &run.ServiceStatus{}
+		}
+		svc.Status.LatestCreatedRevisionName = svc.Spec.Template.Metadata.Name
+	}
+	s.svc = svc
+	if svc.Spec != nil && svc.Spec.Template != nil {
+		s.addRevision()
+	}
+}
+
+// addRevision records the revision described by the service's current template.
+func (s *server) addRevision() {
+	meta := s.svc.Spec.Template.Metadata
+	annotations := make(map[string]string, len(meta.Annotations))
+	for k, v := range meta.Annotations {
+		annotations[k] = v
+	}
+	// creation times one second apart, so that revisions sort in the order created
+	created := time.Date(2024, time.January, 1, 0, 0, len(s.revisions), 0, time.UTC)
+	s.revisions = append(s.revisions, &run.Revision{
+		Metadata: &run.ObjectMeta{
+			Name:              meta.Name,
+			Namespace:         s.svc.Metadata.Namespace,
+			Annotations:       annotations,
+			Labels:            map[string]string{"serving.knative.dev/service": s.svc.Metadata.Name},
+			CreationTimestamp: created.Format(time.RFC3339),
+		},
+		Status: &run.RevisionStatus{
+			Conditions: []*run.GoogleCloudRunV1Condition{{Type: "Ready", Status: "True"}},
+		},
+	})
+}
+
+// serveRevisions answers a GET of a revision or of the list of revisions.
+func (s *server) serveRevisions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, s.revisionsPath), "/")
+	if name == "" {
+		json.NewEncoder(w).Encode(run.ListRevisionsResponse{Items: s.revisions})
+		return
+	}
+	for _, rev := range s.revisions {
+		if rev.Metadata.Name == name {
+			json.NewEncoder(w).Encode(rev)
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// clone returns a deep copy of svc.
+func clone(t *testing.T, svc *run.Service) *run.Service {
+	b, err := json.Marshal(svc)
+	if err != nil {
+		t.Fatalf("scaletest: encoding initial service: %v", err)
+	}
+	var c run.Service
+	if err := json.Unmarshal(b, &c); err != nil {
+		t.Fatalf("scaletest: decoding initial service: %v", err)
+	}
+	return &c
+}
@@ -0,0 +1,101 @@
+package scaletest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	scale "github.com/darrenmcc/run-scaler"
+	"github.com/darrenmcc/run-scaler/scaletest"
+	"google.golang.org/api/run/v1"
+)
+
+func TestNewTestServer(t *testing.T) {
+	srv, client := scaletest.NewTestServer(t, &run.Service{
+		Metadata: &run.ObjectMeta{Name: "my-svc"},
+		Spec: &run.ServiceSpec{Template: &run.RevisionTemplate{
+			Metadata: &run.ObjectMeta{Annotations: map[string]string{
+				"autoscaling.knative.dev/minScale": "0",
+				"autoscaling.knative.dev/maxScale": "10",
+			}},
+		}},
+	}, scale.WithRegion("us-central1"))
+
+	if err := client.Scale(context.Background(), 0, 20); err != nil {
+		t.Fatalf("Scale: %v", err)
+	}
+
+	reqs := scaletest.Requests(srv)
+	if len(reqs) != 2 || reqs[0].Method != http.MethodGet || reqs[1].Method != http.MethodPut {
+		t.Fatalf("requests = %+v, want a GET then a PUT", reqs)
+	}
+	var put run.Service
+	if err := json.Unmarshal(reqs[1].Body, &put); err != nil {
+		t.Fatalf("decoding PUT body: %v", err)
+	}
+	if got := put.Spec.Template.Metadata.Annotations["autoscaling.knative.dev/maxScale"]; got != "20" {
+		t.Errorf("PUT maxScale = %q, want 20", got)
+	}
+	if put.Metadata.ResourceVersion != "1" {
+		t.Errorf("PUT resourceVersion = %q, want the one read, 1", put.Metadata.ResourceVersion)
+	}
+
+	stored := scaletest.Service(srv)
+	if got := stored.Spec.Template.Metadata.Annotations["autoscaling.knative.dev/maxScale"]; got != "20" {
+		t.Errorf("stored maxScale = %q, want 20", got)
+	}
+	if stored.Metadata.ResourceVersion != "2" {
+		t.Errorf("stored resourceVersion = %q, want 2", stored.Metadata.ResourceVersion)
+	}
+
+	min, max, err := client.GetScaling(context.Background())
+	if err != nil || min != 0 || max != 20 {
+		t.Errorf("GetScaling = %d, %d, %v, want 0, 20, nil", min, max, err)
+	}
+}
+
+func TestNewTestServerNoop(t *testing.T) {
+	srv, client := scaletest.NewTestServer(t, &run.Service{
+		Metadata: &run.ObjectMeta{Name: "my-svc"},
+		Spec: &run.ServiceSpec{Template: &run.RevisionTemplate{
+			Metadata: &run.ObjectMeta{Annotations: map[string]string{
+				"autoscaling.knative.dev/minScale": "1",
+				"autoscaling.knative.dev/maxScale": "10",
+			}},
+		}},
+	}, scale.WithRegion("us-central1"))
+
+	if err := client.Scale(context.Background(), 1, 10); err != nil {
+		t.Fatalf("Scale: %v", err)
+	}
+	for _, r := range scaletest.Requests(srv) {
+		if r.Method == http.MethodPut {
+			t.Fatalf("noop scale sent a PUT: %s", r.Body)
+		}
+	}
+}
+
+func TestNewTestServerRevisions(t *testing.T) {
+	srv, client := scaletest.NewTestServer(t, &run.Service{
+		Metadata: &run.ObjectMeta{Name: "my-svc"},
+		Spec: &run.ServiceSpec{Template: &run.RevisionTemplate{
+			Metadata: &run.ObjectMeta{Name: "my-svc-00001-abc"},
+		}},
+	})
+
+	if err := client.Scale(context.Background(), 1, 10); err != nil {
+		t.Fatalf("Scale: %v", err)
+	}
+	revision := scaletest.Service(srv).Status.LatestCreatedRevisionName
+	opts := []scale.ScaleOption{
+		scale.WithKnativeEndpoint(srv.URL),
+		scale.WithHTTPClient(srv.Client()),
+		scale.WithProject("default"),
+		scale.WithService("my-svc"),
+	}
+	if err := scale.WaitForReady(context.Background(), revision, time.Millisecond, opts...); err != nil {
+		t.Errorf("WaitForReady(%s): %v", revision, err)
+	}
+}
@@ -0,0 +1,125 @@
+package scale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// Schedule applies different min and max instances depending on the time of week e.g.
+//
+//	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+//	sched, err := scale.NewSchedule().
+//	    AddWindow(weekdays, 8*time.Hour, 20*time.Hour, 5, 100).
+//	    Otherwise(0, 20).
+//	    Build()
+//	...
+//	err = sched.Apply(ctx, time.Now())
+type Schedule struct {
+	windows  []window
+	fallback *window
+	built    bool
+}
+
+type window struct {
+	days       []time.Weekday
+	start, end time.Duration
+	min, max   int
+}
+
+// NewSchedule returns an empty Schedule.
+func NewSchedule() *Schedule {
+	return &Schedule{}
+}
+
+// AddWindow applies min and max on the given days between start and end, measured
+// from midnight in the location of the time passed to Apply. Windows cannot span
+// midnight; split them into two windows instead.
+func (s *Schedule) AddWindow(days []time.Weekday, start, end time.Duration, min, max int) *Schedule {
+	s.windows = append(s.windows, window{days: days, start: start, end: end, min: min, max: max})
+	s.built = false
+	return s
+}
+
+// Otherwise applies min and max at any time not covered by a window.
+func (s *Schedule) Otherwise(min, max int) *Schedule {
+	s.fallback = &window{min: min, max: max}
+	s.built = false
+	return s
+}
+
+// Build checks that no windows overlap and that, unless Otherwise was called, every
+// moment of the week is covered by a window. It must succeed before calling Apply.
+func (s *Schedule) Build() (*Schedule, error) {
+	perDay := make(map[time.Weekday][]window)
+	for _, w := range s.windows {
+		if w.start < 0 || w.end > day || w.start >= w.end {
+			return nil, fmt.Errorf("schedule window %s-%s: start must be before end and within a single day", w.start, w.end)
+		}
+		if err := validate(w.min, w.max); err != nil {
+			return nil, err
+		}
+		for _, d := range w.days {
+			perDay[d] = append(perDay[d], w)
+		}
+	}
+	if s.fallback != nil {
+		if err := validate(s.fallback.min, s.fallback.max); err != nil {
+			return nil, err
+		}
+	}
+
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		ws := perDay[d]
+		sort.Slice(ws, func(i, j int) bool { return ws[i].start < ws[j].start })
+		var covered time.Duration
+		for i, w := range ws {
+			if i > 0 && w.start < ws[i-1].end {
+				return nil, fmt.Errorf("schedule windows overlap on %s: %s-%s and %s-%s",
+					d, ws[i-1].start, ws[i-1].end, w.start, w.end)
+			}
+			if w.start == covered {
+				covered = w.end
+			}
+		}
+		if s.fallback == nil && covered != day {
+			return nil, fmt.Errorf("schedule does not cover all of %s from %s; add windows or call Otherwise", d, covered)
+		}
+	}
+
+	s.built = true
+	return s, nil
+}
+
+// Apply scales the service to the values of the window containing now.
+func (s *Schedule) Apply(ctx context.Context, now time.Time, opts ...ScaleOption) error {
+	if !s.built {
+		return errors.New("schedule must be built before it is applied")
+	}
+	w := s.at(now)
+	return ScaleMinMax(ctx, w.min, w.max, opts...)
+}
+
+// at returns the window containing t.
+func (s *Schedule) at(t time.Time) window {
+	// wall-clock time of day, which on days with a DST change differs from the time
+	// elapsed since midnight
+	h, m, sec := t.Clock()
+	offset := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec)*time.Second
+	for _, w := range s.windows {
+		if offset < w.start || offset >= w.end {
+			continue
+		}
+		for _, d := range w.days {
+			if d == t.Weekday() {
+				return w
+			}
+		}
+	}
+	// Build guarantees a fallback whenever the windows leave a gap
+	return *s.fallback
+}
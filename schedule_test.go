@@ -0,0 +1,51 @@
+package scale_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	scale "github.com/darrenmcc/run-scaler"
+	"github.com/darrenmcc/run-scaler/scaletest"
+)
+
+func TestScheduleDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+	sunday := []time.Weekday{time.Sunday}
+	others := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+	sched, err := scale.NewSchedule().
+		AddWindow(sunday, 0, 10*time.Hour, 1, 5).
+		AddWindow(sunday, 10*time.Hour, 23*time.Hour, 2, 10).
+		AddWindow(sunday, 23*time.Hour, 24*time.Hour, 3, 15).
+		AddWindow(others, 0, 24*time.Hour, 1, 5).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		wantMin string
+	}{
+		// 25-hour day: an hour more has elapsed since midnight than the clock shows
+		{"fall back, late evening", time.Date(2026, time.November, 1, 23, 30, 0, 0, loc), "3"},
+		// 23-hour day: an hour less
+		{"spring forward, morning", time.Date(2026, time.March, 8, 10, 30, 0, 0, loc), "2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _ := scaletest.NewTestServer(t, newService(nil))
+			if err := sched.Apply(context.Background(), tt.now, serverOptions(srv)...); err != nil {
+				t.Fatal(err)
+			}
+			annotations := scaletest.Service(srv).Spec.Template.Metadata.Annotations
+			if got := annotations["autoscaling.knative.dev/minScale"]; got != tt.wantMin {
+				t.Errorf("minScale = %q, want %s", got, tt.wantMin)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package scale
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"google.golang.org/api/option"
+)
+
+// ScaleFromSecret scales the service to the values stored in the latest version of a
+// Secret Manager secret, as a JSON payload of the form {"min":5,"max":100}. This lets
+// a platform team change scaling policy centrally without redeploying. secretName is
+// either a full resource name (projects/my-project/secrets/scaling) or just the
+// secret's name, in which case the service's project is used. The secret is read with
+// the same credentials as the service.
+func ScaleFromSecret(ctx context.Context, secretName string, opts ...ScaleOption) error {
+	c, err := NewClient(ctx, opts...)
+	if err != nil {
+		return err
+	}
+
+	// over REST, sharing the client's HTTP client and so its credentials, e.g.
+	// WithCredentialsFile or WithImpersonateServiceAccount
+	sm, err := secretmanager.NewRESTClient(ctx, option.WithHTTPClient(c.httpClient))
+	if err != nil {
+		return err
+	}
+	defer sm.Close()
+
+	name := secretName
+	if !strings.HasPrefix(name, "projects/") {
+		name = fmt.Sprintf("projects/%s/secrets/%s", c.project, name)
+	}
+	resp, err := sm.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{
+		Name: name + "/versions/latest",
+	})
+	if err != nil {
+		return err
+	}
+
+	min, max, err := decodeScaling(bytes.NewReader(resp.GetPayload().GetData()))
+	if err != nil {
+		return fmt.Errorf("secret %s: %w", secretName, err)
+	}
+	return c.Scale(ctx, min, max)
+}
@@ -0,0 +1,46 @@
+package scale
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// SaveSnapshot writes the service's current scaling to w as a JSON ScalingConfig,
+// e.g. to capture a known good state before a major traffic event. Service, Region
+// and Project are included so the snapshot identifies the service on its own.
+func SaveSnapshot(ctx context.Context, w io.Writer, opts ...ScaleOption) error {
+	c, err := NewClient(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, cfg := c.config(ctx)
+	info, err := c.scalingInfo(ctx, cfg.Service)
+	if err != nil {
+		return err
+	}
+	snapshot := ScalingConfig{
+		Service:           info.Service,
+		Region:            info.Region,
+		Project:           info.Project,
+		Min:               info.Min,
+		Max:               info.Max,
+		ConcurrencyTarget: info.Concurrency,
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(snapshot)
+}
+
+// RestoreSnapshot scales the service back to a snapshot written by SaveSnapshot.
+// opts take precedence over the snapshot, e.g. WithService restores it onto another
+// service.
+func RestoreSnapshot(ctx context.Context, r io.Reader, opts ...ScaleOption) error {
+	snapshot, err := LoadConfig(r)
+	if err != nil {
+		return err
+	}
+	_, err = Scale(ctx, withOptions(snapshot.options(), opts...)...)
+	return err
+}
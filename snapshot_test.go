@@ -0,0 +1,40 @@
+package scale_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	scale "github.com/darrenmcc/run-scaler"
+	"github.com/darrenmcc/run-scaler/scaletest"
+)
+
+func TestSnapshotUnsetMax(t *testing.T) {
+	srv, c := scaletest.NewTestServer(t, newService(map[string]string{
+		"autoscaling.knative.dev/minScale": "2",
+	}))
+	opts := append(serverOptions(srv), scale.WithRegion("us-central1"))
+
+	var snapshot bytes.Buffer
+	if err := scale.SaveSnapshot(context.Background(), &snapshot, opts...); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(snapshot.String(), `"max"`) {
+		t.Errorf("snapshot has a max for a service without one:\n%s", snapshot.String())
+	}
+
+	if err := c.Scale(context.Background(), 5, 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := scale.RestoreSnapshot(context.Background(), &snapshot, opts...); err != nil {
+		t.Fatal(err)
+	}
+	annotations := scaletest.Service(srv).Spec.Template.Metadata.Annotations
+	if got := annotations["autoscaling.knative.dev/minScale"]; got != "2" {
+		t.Errorf("minScale = %q, want 2", got)
+	}
+	if got, ok := annotations["autoscaling.knative.dev/maxScale"]; ok {
+		t.Errorf("maxScale = %q, want it removed", got)
+	}
+}
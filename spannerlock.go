@@ -0,0 +1,112 @@
+package scale
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"google.golang.org/grpc/codes"
+)
+
+// spannerLock is a LockProvider backed by a Cloud Spanner table. The lock row is
+// read and written in one read-write transaction, which Spanner serializes. The
+// ExpiresAt written by Acquire identifies the holder, so that Release only deletes
+// the row if it has not expired and been taken over since.
+type spannerLock struct {
+	client *spanner.Client
+	table  string
+	ttl    time.Duration
+
+	mu sync.Mutex
+	// held is the ExpiresAt written for each service locked through this provider
+	held map[string]time.Time
+}
+
+// NewSpannerLock returns a LockProvider keeping one row per locked service in table,
+// for use with WithLockProvider, keyed by project/region/service. The table must have
+// the columns:
+//
+//	CREATE TABLE ScaleLocks (
+//	  Service STRING(MAX) NOT NULL,
+//	  ExpiresAt TIMESTAMP NOT NULL,
+//	) PRIMARY KEY (Service)
+//
+// Locks left behind by crashed processes expire after 5 minutes and are taken over
+// by the next Acquire.
+func NewSpannerLock(client *spanner.Client, table string) LockProvider {
+	return &spannerLock{client: client, table: table, ttl: defaultLockTTL}
+}
+
+func (l *spannerLock) Acquire(ctx context.Context, service string) error {
+	// tracked separately, since Spanner may wrap errors returned by the transaction
+	var (
+		held    bool
+		expires time.Time
+	)
+	_, err := l.client.ReadWriteTransaction(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+		row, err := txn.ReadRow(ctx, l.table, spanner.Key{service}, []string{"ExpiresAt"})
+		switch {
+		case spanner.ErrCode(err) == codes.NotFound:
+		case err != nil:
+			return err
+		default:
+			var expiresAt time.Time
+			if err := row.Columns(&expiresAt); err != nil {
+				return err
+			}
+			if held = time.Now().Before(expiresAt); held {
+				return ErrScaleInProgress
+			}
+		}
+		expires = time.Now().Add(l.ttl).UTC()
+		return txn.BufferWrite([]*spanner.Mutation{
+			spanner.InsertOrUpdate(l.table, []string{"Service", "ExpiresAt"}, []interface{}{service, expires}),
+		})
+	})
+	if held {
+		return ErrScaleInProgress
+	}
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held == nil {
+		l.held = make(map[string]time.Time)
+	}
+	l.held[service] = expires
+	return nil
+}
+
+// Release deletes the lock row if it still carries the ExpiresAt written by Acquire.
+// If the lock expired and was taken over in the meantime, it is left to its new holder.
+func (l *spannerLock) Release(ctx context.Context, service string) error {
+	l.mu.Lock()
+	expires, ok := l.held[service]
+	delete(l.held, service)
+	l.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	_, err := l.client.ReadWriteTransaction(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+		row, err := txn.ReadRow(ctx, l.table, spanner.Key{service}, []string{"ExpiresAt"})
+		switch {
+		case spanner.ErrCode(err) == codes.NotFound:
+			return nil
+		case err != nil:
+			return err
+		}
+		var expiresAt time.Time
+		if err := row.Columns(&expiresAt); err != nil {
+			return err
+		}
+		if !expiresAt.Equal(expires) {
+			// taken over by another holder
+			return nil
+		}
+		return txn.BufferWrite([]*spanner.Mutation{spanner.Delete(l.table, spanner.Key{service})})
+	})
+	return err
+}
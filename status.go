@@ -0,0 +1,90 @@
+package scale
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// lastScaled holds when each service was last updated by this process, keyed by
+// statusKey.
+var lastScaled sync.Map
+
+func statusKey(project, region, service string) string {
+	return project + "/" + region + "/" + service
+}
+
+var statusTemplate = template.Must(template.New("status").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Service}} scaling</title></head>
+<body>
+<h1>{{.Service}}</h1>
+<table>
+<tr><th align="left">Project</th><td>{{.Project}}</td></tr>
+<tr><th align="left">Region</th><td>{{.Region}}</td></tr>
+{{- if .Error}}
+<tr><th align="left">Error</th><td>{{.Error}}</td></tr>
+{{- else}}
+<tr><th align="left">Min instances</th><td>{{.Min}}</td></tr>
+<tr><th align="left">Max instances</th><td>{{.Max}}</td></tr>
+{{- end}}
+<tr><th align="left">Last scaled</th><td>{{if .LastScaled.IsZero}}unknown{{else}}{{.LastScaled.UTC.Format "2006-01-02 15:04:05 MST"}}{{end}}</td></tr>
+</table>
+</body>
+</html>
+`))
+
+type statusPage struct {
+	Service, Project, Region string
+	Min, Max                 int
+	LastScaled               time.Time
+	Error                    string
+}
+
+// NewStatusHandler returns a handler rendering a plain HTML page with the service's
+// current min and max instances and when it was last scaled, for operators e.g.
+// router.HandleFunc("/scale/status", scale.NewStatusHandler())
+// The last scaling comes from the store set with WithHistoryStore, or else from the
+// updates made by this process since it started. If the service cannot be read the
+// page shows the error with status 500.
+func NewStatusHandler(opts ...ScaleOption) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		status := http.StatusOK
+		page := statusPage{}
+		c, err := NewClient(ctx, opts...)
+		if err == nil {
+			c, cfg := c.config(ctx)
+			page.Service, page.Project, page.Region = cfg.Service, c.project, cfg.Region
+			page.LastScaled = c.lastScaled(ctx, &cfg)
+			page.Min, page.Max, err = c.GetScaling(ctx)
+		}
+		if err != nil {
+			status = http.StatusInternalServerError
+			page.Error = err.Error()
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(status)
+		statusTemplate.Execute(w, page)
+	}
+}
+
+// lastScaled returns when the service was last updated, or the zero time if unknown.
+func (c *Client) lastScaled(ctx context.Context, cfg *Config) time.Time {
+	if cfg.historyStore != nil {
+		events, err := cfg.historyStore.List(ctx, cfg.Service, 1)
+		if err != nil {
+			cfg.log(ctx, "status: reading history failed", "service", cfg.Service, "error", err)
+		}
+		if len(events) > 0 {
+			return events[0].Timestamp
+		}
+		return time.Time{}
+	}
+	if t, ok := lastScaled.Load(statusKey(c.project, cfg.Region, cfg.Service)); ok {
+		return t.(time.Time)
+	}
+	return time.Time{}
+}
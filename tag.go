@@ -0,0 +1,51 @@
+package scale
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/api/run/v1"
+)
+
+// WithTag gives the revision Scale creates the traffic tag tag, e.g. "canary", without
+// sending it any share of traffic, so that it can be smoke tested at its own
+// https://<tag>---<service URL> before a rollout. The tag is moved off whatever
+// revision had it. It is set with a second update once the revision exists, so a
+// failure to tag is logged (see WithLogger) and does not fail Scale. Nothing is
+// tagged on a noop. APIv2 ignores it.
+func WithTag(tag string) ScaleOption {
+	return func(c *Config) {
+		if !revisionNamePattern.MatchString(tag) {
+			c.err = &ConfigError{Field: "Tag", Message: fmt.Sprintf("%q must be lowercase letters, digits and hyphens, starting with a letter", tag)}
+			return
+		}
+		c.tag = tag
+	}
+}
+
+// tagRevision points cfg's tag at the revision just created, on svc as returned by
+// the update, and returns the service as updated.
+func (c *Client) tagRevision(ctx context.Context, cfg *Config, svc *run.Service, revision string) (*run.Service, error) {
+	if svc.Spec == nil {
+		return nil, fmt.Errorf("update service %s: response has no spec", cfg.Service)
+	}
+	traffic := []*run.TrafficTarget{{RevisionName: revision, Tag: cfg.tag}}
+	for _, t := range svc.Spec.Traffic {
+		if t.Tag == cfg.tag {
+			if t.Percent == 0 {
+				continue
+			}
+			// keep the traffic, only the tag moves
+			t.Tag = ""
+		}
+		traffic = append(traffic, t)
+	}
+	svc.Spec.Traffic = traffic
+
+	b, err := json.Marshal(svc)
+	if err != nil {
+		return nil, err
+	}
+	return c.update(ctx, cfg, b)
+}
@@ -0,0 +1,169 @@
+package scale
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	cloudtasks "cloud.google.com/go/cloudtasks/apiv2"
+	"cloud.google.com/go/cloudtasks/apiv2/cloudtaskspb"
+	"google.golang.org/api/idtoken"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultTaskPath is where ScheduleScale expects the service to serve
+// NewCloudTasksHandler unless WithTaskURL is given.
+const defaultTaskPath = "/scale"
+
+// WithTaskURL sets the URL of the scaling endpoint that tasks created by ScheduleScale
+// call, instead of /scale on the service's own URL.
+func WithTaskURL(url string) ScaleOption {
+	return func(c *Config) {
+		c.taskURL = url
+	}
+}
+
+// WithTaskServiceAccount makes tasks created by ScheduleScale authenticate with an
+// OIDC token for the given service account, e.g. one allowed to invoke the service.
+func WithTaskServiceAccount(email string) ScaleOption {
+	return func(c *Config) {
+		c.taskServiceAccount = email
+	}
+}
+
+// ScheduleScale enqueues a Cloud Tasks HTTP task that, at the given time, POSTs
+// {"min":<min>,"max":<max>} to the service's scaling endpoint, e.g. to scale up in
+// 45 minutes without keeping a timer running. That is /scale on the service's own
+// URL unless WithTaskURL is given, and is typically served by NewCloudTasksHandler.
+// queue is either a full queue name
+// (projects/p/locations/l/queues/q) or just the queue's ID in the service's project
+// and region. The values are validated now, as well as when the task runs.
+func ScheduleScale(ctx context.Context, tasksClient *cloudtasks.Client, queue string, at time.Time, min, max int, opts ...ScaleOption) error {
+	c, err := NewClient(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	c, cfg := c.config(ctx, WithMin(min), WithMax(max))
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	url := cfg.taskURL
+	if url == "" {
+		svc, err := c.get(ctx, &cfg)
+		if err != nil {
+			return err
+		}
+		if svc.Status == nil || svc.Status.Url == "" {
+			return errors.New("service has no URL; use WithTaskURL")
+		}
+		url = strings.TrimSuffix(svc.Status.Url, "/") + defaultTaskPath
+	}
+	body, err := json.Marshal(struct {
+		Min int `json:"min"`
+		Max int `json:"max"`
+	}{min, max})
+	if err != nil {
+		return err
+	}
+
+	req := &cloudtaskspb.HttpRequest{
+		Url:        url,
+		HttpMethod: cloudtaskspb.HttpMethod_POST,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       body,
+	}
+	if cfg.taskServiceAccount != "" {
+		req.AuthorizationHeader = &cloudtaskspb.HttpRequest_OidcToken{
+			OidcToken: &cloudtaskspb.OidcToken{ServiceAccountEmail: cfg.taskServiceAccount},
+		}
+	}
+	parent := queue
+	if !strings.HasPrefix(parent, "projects/") {
+		parent = fmt.Sprintf("projects/%s/locations/%s/queues/%s", c.project, cfg.Region, queue)
+	}
+	_, err = tasksClient.CreateTask(ctx, &cloudtaskspb.CreateTaskRequest{
+		Parent: parent,
+		Task: &cloudtaskspb.Task{
+			ScheduleTime: timestamppb.New(at),
+			MessageType:  &cloudtaskspb.Task_HttpRequest{HttpRequest: req},
+		},
+	})
+	return err
+}
+
+// NewCloudTasksHandler returns a handler for the tasks created by ScheduleScale, or any
+// Cloud Tasks HTTP task with a {"min":N,"max":M} body, that scales the service to
+// the values in the body. Responses have a JSON body like NewDynamicHandler's.
+//
+// With WithTaskServiceAccount, only tasks carrying an OIDC token issued to that
+// service account are accepted; the token's audience must be the URL set with
+// WithTaskURL or, without it, the URL the request was made to, which is what Cloud
+// Tasks uses by default. Others are refused with 401.
+//
+// Cloud Tasks retries every task that fails, within the queue's retry config, so the
+// status code mostly tells apart failures in the queue's logs: 400 for a body that
+// will never succeed, 503 for failures that may pass, such as a conflicting update or
+// a held lock, and 500 for anything else.
+func NewCloudTasksHandler(opts ...ScaleOption) http.HandlerFunc {
+	cfg := newConfig(opts)
+	return cfg.wrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cfg.taskServiceAccount != "" {
+			if err := cfg.verifyTaskToken(r); err != nil {
+				writeError(w, http.StatusUnauthorized, err)
+				return
+			}
+		}
+		min, max, err := decodeScaling(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := validate(min, max); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		result, err := Scale(r.Context(), withOptions(opts, WithMin(min), WithMax(max))...)
+		if err != nil {
+			writeError(w, taskStatus(err), err)
+			return
+		}
+		writeScaleResult(w, result, nil, true)
+	})).ServeHTTP
+}
+
+// verifyTaskToken checks the request carries an OIDC token for the task service
+// account.
+func (c *Config) verifyTaskToken(r *http.Request) error {
+	token, ok := bearerToken(r)
+	if !ok {
+		return errors.New("missing bearer token")
+	}
+	audience := c.taskURL
+	if audience == "" {
+		audience = "https://" + r.Host + r.URL.Path
+	}
+	payload, err := idtoken.Validate(r.Context(), token, audience)
+	if err != nil {
+		return fmt.Errorf("invalid token: %w", err)
+	}
+	if email, _ := payload.Claims["email"].(string); email != c.taskServiceAccount {
+		return fmt.Errorf("token is not for service account %s", c.taskServiceAccount)
+	}
+	if verified, _ := payload.Claims["email_verified"].(bool); !verified {
+		return errors.New("token email is not verified")
+	}
+	return nil
+}
+
+// taskStatus returns the status code reporting a failed Scale to Cloud Tasks.
+func taskStatus(err error) int {
+	if transient(err) {
+		return http.StatusServiceUnavailable
+	}
+	return errorStatus(err)
+}
@@ -0,0 +1,154 @@
+package scale
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/run/v1"
+)
+
+// applyTemplate sets the values requested in cfg on svc's revision template, and the
+// ingress on svc itself, leaving anything cfg doesn't ask for untouched, and reports
+// whether anything changed.
+func applyTemplate(svc *run.Service, cfg *Config) bool {
+	tmpl := svc.Spec.Template
+	annotations := tmpl.Metadata.Annotations
+
+	changed := setAnnotation(annotations, minScaleAnnotation, strconv.Itoa(cfg.Min))
+	switch {
+	case cfg.clearsMax():
+		if _, ok := annotations[maxScaleAnnotation]; ok {
+			delete(annotations, maxScaleAnnotation)
+			changed = true
+		}
+	case !cfg.keepsMax():
+		changed = setAnnotation(annotations, maxScaleAnnotation, strconv.Itoa(cfg.revisionMax())) || changed
+	}
+	if cfg.Concurrency > 0 {
+		changed = setAnnotation(annotations, targetAnnotation, strconv.Itoa(cfg.Concurrency)) || changed
+		if tmpl.Spec.ContainerConcurrency != int64(cfg.Concurrency) {
+			tmpl.Spec.ContainerConcurrency = int64(cfg.Concurrency)
+			changed = true
+		}
+	}
+	if cfg.StartupCPUBoost != nil {
+		changed = setAnnotation(annotations, startupCPUBoostAnnotation, strconv.FormatBool(*cfg.StartupCPUBoost)) || changed
+	}
+	if cfg.SessionAffinity != nil {
+		changed = setAnnotation(annotations, sessionAffinityAnnotation, strconv.FormatBool(*cfg.SessionAffinity)) || changed
+	}
+	if cfg.ExecutionEnvironment != "" {
+		changed = setAnnotation(annotations, executionEnvironmentAnnotation, string(cfg.ExecutionEnvironment)) || changed
+	}
+	if cfg.VPCConnector != "" {
+		changed = setAnnotation(annotations, vpcConnectorAnnotation, cfg.VPCConnector) || changed
+	}
+	if cfg.VPCEgress != "" {
+		changed = setAnnotation(annotations, vpcEgressAnnotation, cfg.VPCEgress) || changed
+	}
+	if cfg.CPU > 0 || cfg.Memory > 0 {
+		changed = setResources(tmpl.Spec, cfg.CPU, cfg.Memory) || changed
+	}
+	if cfg.Ingress != "" {
+		changed = setAnnotation(svc.Metadata.Annotations, ingressAnnotation, string(cfg.Ingress)) || changed
+	}
+	return changed
+}
+
+// setAnnotation sets key to v and reports whether that changed the annotations.
+func setAnnotation(annotations map[string]string, key, v string) bool {
+	if annotations[key] == v {
+		return false
+	}
+	annotations[key] = v
+	return true
+}
+
+// setResources sets the CPU and memory limits of the serving container, skipping
+// any that are zero or already equal, and reports whether anything changed.
+func setResources(spec *run.RevisionSpec, millicores, megabytes int) bool {
+	if len(spec.Containers) == 0 {
+		return false
+	}
+	container := spec.Containers[0]
+	if container.Resources == nil {
+		container.Resources = &run.ResourceRequirements{}
+	}
+	if container.Resources.Limits == nil {
+		container.Resources.Limits = make(map[string]string)
+	}
+	limits := container.Resources.Limits
+
+	var changed bool
+	if millicores > 0 {
+		if current, ok := parseMillicores(limits["cpu"]); !ok || current != millicores {
+			limits["cpu"] = fmt.Sprintf("%dm", millicores)
+			changed = true
+		}
+	}
+	if megabytes > 0 {
+		if current, ok := parseMegabytes(limits["memory"]); !ok || current != megabytes {
+			limits["memory"] = fmt.Sprintf("%dMi", megabytes)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// parseMillicores parses a Kubernetes CPU quantity such as "1", "0.5" or "500m".
+func parseMillicores(q string) (int, bool) {
+	if v, ok := strings.CutSuffix(q, "m"); ok {
+		n, err := strconv.Atoi(v)
+		return n, err == nil
+	}
+	f, err := strconv.ParseFloat(q, 64)
+	if err != nil {
+		return 0, false
+	}
+	return int(f * 1000), true
+}
+
+// memoryUnits are the Kubernetes memory suffixes Cloud Run accepts, in MiB.
+var memoryUnits = []struct {
+	suffix string
+	mib    float64
+}{
+	{"Gi", 1024},
+	{"Mi", 1},
+	{"Ki", 1.0 / 1024},
+	{"G", 1e9 / (1 << 20)},
+	{"M", 1e6 / (1 << 20)},
+	{"k", 1e3 / (1 << 20)},
+}
+
+// parseMegabytes parses a Kubernetes memory quantity such as "512Mi" or "2Gi" into MiB.
+func parseMegabytes(q string) (int, bool) {
+	for _, u := range memoryUnits {
+		if v, ok := strings.CutSuffix(q, u.suffix); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return 0, false
+			}
+			return int(f * u.mib), true
+		}
+	}
+	return 0, false
+}
+
+// splitTraffic routes percent of traffic to the revision about to be created and the
+// rest to the revision currently serving. If no revision is serving yet, the new one
+// gets all traffic.
+func splitTraffic(svc *run.Service, percent int) {
+	if svc.Status == nil || svc.Status.LatestReadyRevisionName == "" {
+		percent = 100
+	}
+	traffic := []*run.TrafficTarget{{LatestRevision: true, Percent: int64(percent)}}
+	if percent < 100 {
+		traffic = append(traffic, &run.TrafficTarget{
+			RevisionName: svc.Status.LatestReadyRevisionName,
+			Percent:      int64(100 - percent),
+		})
+	}
+	svc.Spec.Traffic = traffic
+}
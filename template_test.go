@@ -0,0 +1,37 @@
+package scale_test
+
+import (
+	"context"
+	"testing"
+
+	scale "github.com/darrenmcc/run-scaler"
+	"github.com/darrenmcc/run-scaler/scaletest"
+)
+
+func TestScaleNilAnnotations(t *testing.T) {
+	// neither the service nor its template has any annotations yet
+	srv, _ := scaletest.NewTestServer(t, newService(nil))
+
+	_, err := scale.Scale(context.Background(), append(serverOptions(srv), scale.WithMin(2), scale.WithMax(10))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := scaletest.Service(srv)
+	if svc.Metadata.Annotations == nil {
+		t.Fatal("service annotations not initialised")
+	}
+	if got := svc.Metadata.Annotations["run.googleapis.com/launch-stage"]; got != "BETA" {
+		t.Errorf("launch stage = %q, want BETA", got)
+	}
+	annotations := svc.Spec.Template.Metadata.Annotations
+	if annotations == nil {
+		t.Fatal("template annotations not initialised")
+	}
+	if got := annotations["autoscaling.knative.dev/minScale"]; got != "2" {
+		t.Errorf("minScale = %q, want 2", got)
+	}
+	if got := annotations["autoscaling.knative.dev/maxScale"]; got != "10" {
+		t.Errorf("maxScale = %q, want 10", got)
+	}
+}
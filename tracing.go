@@ -0,0 +1,44 @@
+package scale
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const tracerName = "github.com/darrenmcc/run-scaler"
+
+// call sends a request to the Cloud Run API inside a span with the given name.
+// The response must be closed by the caller.
+func (c *Config) call(ctx context.Context, httpClient *http.Client, spanName, project, method, url string, body []byte) (*http.Response, error) {
+	tp := c.tracerProvider
+	if tp == nil {
+		tp = noop.NewTracerProvider()
+	}
+	ctx, span := tp.Tracer(tracerName).Start(ctx, spanName, trace.WithAttributes(
+		attribute.String("gcp.project_id", project),
+		attribute.String("cloud_run.service", c.Service),
+		attribute.String("cloud.region", c.Region),
+	))
+	defer span.End()
+
+	start := time.Now()
+	resp, err := c.retry.do(ctx, httpClient, method, url, body)
+	c.metrics.observeCall(c, strings.TrimPrefix(spanName, "run-scaler/"), start)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
@@ -0,0 +1,83 @@
+package scale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/api/run/v1"
+)
+
+// WaitForReady polls the revision every pollInterval until its Ready condition is
+// True, e.g. to hold traffic back until the revision created by Scale can serve it.
+// revisionName is usually ScaleResult.RevisionName. A revision not found yet is
+// waited for, since it may not be visible right after the update. WaitForReady
+// returns an error if the revision fails to become ready, and ctx.Err() if ctx is
+// done first, so bound the wait with a deadline. A pollInterval that is not positive
+// is a *ConfigError.
+func WaitForReady(ctx context.Context, revisionName string, pollInterval time.Duration, opts ...ScaleOption) error {
+	c, err := NewClient(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	c, cfg := c.config(ctx)
+	return c.waitForReady(ctx, &cfg, revisionName, pollInterval)
+}
+
+func (c *Client) waitForReady(ctx context.Context, cfg *Config, revisionName string, pollInterval time.Duration) error {
+	if err := checkInterval("pollInterval", pollInterval); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		ready, err := c.revisionReady(ctx, cfg, revisionName)
+		if ready || err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// revisionReady reports whether the revision is ready, returning an error if it
+// failed.
+func (c *Client) revisionReady(ctx context.Context, cfg *Config, name string) (bool, error) {
+	rev, err := c.getRevision(ctx, cfg, name)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	cond := readyCondition(rev)
+	if cond == nil {
+		return false, nil
+	}
+	switch cond.Status {
+	case "True":
+		return true, nil
+	case "False":
+		return false, fmt.Errorf("revision %s is not ready: %s: %s", name, cond.Reason, cond.Message)
+	}
+	return false, nil
+}
+
+// readyCondition returns the revision's Ready condition, or nil if it has none yet.
+func readyCondition(rev *run.Revision) *run.GoogleCloudRunV1Condition {
+	if rev.Status == nil {
+		return nil
+	}
+	for _, cond := range rev.Status.Conditions {
+		if cond.Type == "Ready" {
+			return cond
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package scale
+
+import (
+	"context"
+	"time"
+)
+
+// Watch checks the service's scaling every interval, starting immediately, and calls
+// onChange whenever it differs from desired, e.g. after someone changed it in the
+// console, with gcloud or Terraform. With WithAutoCorrect the service is then scaled
+// back to desired. Only the Min and Max of desired are used; the service is the one
+// selected by opts. Errors reading or correcting the service are logged (see
+// WithLogger) and polling continues. Watch blocks until ctx is done and returns
+// ctx.Err(). An interval that is not positive is a *ConfigError.
+func Watch(ctx context.Context, interval time.Duration, desired ScalingConfig, onChange func(current, desired ScalingConfig), opts ...ScaleOption) error {
+	if err := checkInterval("interval", interval); err != nil {
+		return err
+	}
+	c, err := NewClient(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	c, cfg := c.config(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		c.checkDrift(ctx, &cfg, desired, onChange)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Client) checkDrift(ctx context.Context, cfg *Config, desired ScalingConfig, onChange func(current, desired ScalingConfig)) {
+	min, max, err := c.GetScaling(ctx)
+	if err != nil {
+		cfg.log(ctx, "watch: reading service failed", "service", cfg.Service, "error", err)
+		return
+	}
+	current := ScalingConfig{Min: min, Max: max}
+	if current.Min == desired.Min && current.Max == desired.Max {
+		return
+	}
+	if onChange != nil {
+		onChange(current, desired)
+	}
+	if !cfg.AutoCorrect {
+		return
+	}
+	if err := c.Scale(ctx, desired.Min, desired.Max); err != nil {
+		cfg.log(ctx, "watch: correcting service failed", "service", cfg.Service, "error", err)
+	}
+}
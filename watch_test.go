@@ -0,0 +1,35 @@
+package scale_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	scale "github.com/darrenmcc/run-scaler"
+	"github.com/darrenmcc/run-scaler/scaletest"
+)
+
+func TestWatchZeroInterval(t *testing.T) {
+	err := scale.Watch(context.Background(), 0, scale.ScalingConfig{Min: 1, Max: 10}, func(current, desired scale.ScalingConfig) {})
+	var configErr *scale.ConfigError
+	if !errors.As(err, &configErr) || configErr.Field != "interval" {
+		t.Errorf("Watch error = %v, want a *ConfigError for interval", err)
+	}
+}
+
+func TestWatchMetricZeroInterval(t *testing.T) {
+	err := scale.WatchMetric(context.Background(), nil, "custom.googleapis.com/queue_depth", &scale.Policy{}, 0)
+	var configErr *scale.ConfigError
+	if !errors.As(err, &configErr) || configErr.Field != "interval" {
+		t.Errorf("WatchMetric error = %v, want a *ConfigError for interval", err)
+	}
+}
+
+func TestWaitForReadyZeroInterval(t *testing.T) {
+	srv, _ := scaletest.NewTestServer(t, newService(nil))
+	err := scale.WaitForReady(context.Background(), "my-svc-00001-tst", 0, serverOptions(srv)...)
+	var configErr *scale.ConfigError
+	if !errors.As(err, &configErr) || configErr.Field != "pollInterval" {
+		t.Errorf("WaitForReady error = %v, want a *ConfigError for pollInterval", err)
+	}
+}
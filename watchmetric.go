@@ -0,0 +1,79 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+)
+
+// WatchMetric reads the latest value of the Cloud Monitoring metric metricType in the
+// service's project every interval, starting immediately, and scales the service to
+// what policy.Evaluate returns for it whenever that differs from the service's
+// current scaling. metricType is e.g. "custom.googleapis.com/queue_depth"; if several
+// time series match, the first one returned is used. Every decision is logged (see
+// WithLogger), as are errors, after which polling continues. WatchMetric blocks until
+// ctx is done and returns ctx.Err(). An interval that is not positive is a
+// *ConfigError.
+func WatchMetric(ctx context.Context, monClient *monitoring.MetricClient, metricType string, policy *Policy, interval time.Duration, opts ...ScaleOption) error {
+	if err := checkInterval("interval", interval); err != nil {
+		return err
+	}
+	c, err := NewClient(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	c, cfg := c.config(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		c.checkMetric(ctx, &cfg, monClient, metricType, policy, interval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Client) checkMetric(ctx context.Context, cfg *Config, monClient *monitoring.MetricClient, metricType string, policy *Policy, interval time.Duration) {
+	// points can take a few minutes to become visible, however short the interval
+	lookback := interval
+	if lookback < backlogLookback {
+		lookback = backlogLookback
+	}
+	p, err := latestPoint(ctx, monClient, c.project, fmt.Sprintf(`metric.type = %q`, metricType), lookback, nil)
+	if err != nil {
+		cfg.log(ctx, "watch metric: reading metric failed", "service", cfg.Service, "metric", metricType, "error", err)
+		return
+	}
+	value := pointValue(p)
+	min, max := policy.Evaluate(value)
+
+	currentMin, currentMax, err := c.GetScaling(ctx)
+	if err != nil {
+		cfg.log(ctx, "watch metric: reading service failed", "service", cfg.Service, "error", err)
+		return
+	}
+	attrs := []any{"service", cfg.Service, "metric", metricType, "value", value, "min", min, "max", max}
+	if currentMin == min && currentMax == max {
+		cfg.log(ctx, "watch metric: service already at policy values", attrs...)
+		return
+	}
+	cfg.log(ctx, "watch metric: scaling service", append(attrs, "old_min", currentMin, "old_max", currentMax)...)
+	if err := c.Scale(ctx, min, max); err != nil {
+		cfg.log(ctx, "watch metric: scaling service failed", append(attrs, "error", err)...)
+	}
+}
+
+// pointValue returns the value of an INT64 or DOUBLE point.
+func pointValue(p *monitoringpb.Point) float64 {
+	v := p.GetValue()
+	if d := v.GetDoubleValue(); d != 0 {
+		return d
+	}
+	return float64(v.GetInt64Value())
+}